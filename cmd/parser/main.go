@@ -5,12 +5,12 @@ import (
 	"log/slog"
 	"os"
 
-	"github.com/LLIEPJIOK/nginxparser/internal/application/parser"
+	"github.com/LLIEPJIOK/nginxparser/pkg/cli"
 )
 
 func main() {
-	if err := parser.Start(); err != nil {
-		slog.Error(fmt.Sprintf("parser.Start(): %s", err))
+	if err := cli.Run(); err != nil {
+		slog.Error(fmt.Sprintf("cli.Run(): %s", err))
 		os.Exit(1)
 	}
 }
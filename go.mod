@@ -3,6 +3,7 @@ module github.com/LLIEPJIOK/nginxparser
 go 1.22.6
 
 require (
+	github.com/klauspost/compress v1.18.0
 	github.com/stretchr/testify v1.9.0
 	golang.org/x/sync v0.8.0
 )
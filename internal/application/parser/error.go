@@ -3,7 +3,7 @@ package parser
 type ErrEmptyLogPath struct{}
 
 func (e ErrEmptyLogPath) Error() string {
-	return "log path is empty"
+	return "log path and nginx config are empty"
 }
 
 type ErrFlag struct {
@@ -3,66 +3,204 @@ package parser
 import (
 	"flag"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
+	_ "time/tzdata"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
 )
 
-const dataLayout = "2006-01-02"
+type pathsFlag []string
+
+func (f *pathsFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+func (f *pathsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+type headersFlag http.Header
+
+func (f headersFlag) String() string {
+	headers := make([]string, 0, len(f))
+
+	for key, values := range f {
+		for _, value := range values {
+			headers = append(headers, key+": "+value)
+		}
+	}
+
+	return strings.Join(headers, ", ")
+}
+
+func (f headersFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(key) == "" {
+		return NewErrFlag(fmt.Sprintf("header: bad header %q", value))
+	}
+
+	http.Header(f).Add(strings.TrimSpace(key), strings.TrimSpace(val))
+
+	return nil
+}
 
 type cmdFlags struct {
-	path     string
-	format   string
-	output   string
-	help     bool
-	timeFrom *time.Time
-	timeTo   *time.Time
+	paths     []string
+	nginxConf string
+	logFormat string
+	jsonKeys  map[string]string
+	detect    int
+	format    string
+	template  string
+	output    string
+	help      bool
+	timeFrom  *time.Time
+	timeTo    *time.Time
+	timeZone  *time.Location
+	bucket    time.Duration
 
 	filterField string
 	filterValue string
+	filter      string
+
+	errorPolicy parser.ErrorPolicy
+	top         parser.TopOptions
+	quantiles   []float64
+	stateFile   string
+	http        parser.HTTPOptions
+
+	follow         bool
+	pollInterval   time.Duration
+	renderInterval time.Duration
 }
 
-func parseTime(timeStr string) (*time.Time, error) {
-	if timeStr != "" {
-		tm, err := time.Parse(dataLayout, timeStr)
-		if err != nil {
-			return nil, fmt.Errorf("parse time: %w", err)
+func parseJSONKeys(keysStr string) (map[string]string, error) {
+	if keysStr == "" {
+		return nil, nil
+	}
+
+	keys := make(map[string]string)
+
+	for _, pair := range strings.Split(keysStr, ",") {
+		key, variable, ok := strings.Cut(pair, "=")
+		if !ok || key == "" || variable == "" {
+			return nil, NewErrFlag(fmt.Sprintf("json-keys: bad pair %q", pair))
 		}
 
-		return &tm, nil
+		keys[strings.TrimSpace(key)] = strings.TrimSpace(variable)
 	}
 
-	return nil, nil
+	return keys, nil
+}
+
+func parseQuantiles(quantilesStr string) ([]float64, error) {
+	quantiles := make([]float64, 0)
+
+	for _, str := range strings.Split(quantilesStr, ",") {
+		if str = strings.TrimSpace(str); str == "" {
+			continue
+		}
+
+		quantile, err := strconv.ParseFloat(str, 64)
+		if err != nil || quantile <= 0 || quantile > 1 {
+			return nil, NewErrFlag(fmt.Sprintf("quantiles: %q is not in (0, 1]", str))
+		}
+
+		quantiles = append(quantiles, quantile)
+	}
+
+	return quantiles, nil
 }
 
 func readCMDFlags() (cmdFlags, error) {
 	var (
-		path   string
-		from   string
-		to     string
-		format string
-		output string
-		help   bool
+		paths     pathsFlag
+		nginxConf string
+		logFormat string
+		jsonKeys  string
+		detect    int
+		from      string
+		to        string
+		last      string
+		tz        string
+		bucketStr string
+		format    string
+		template  string
+		output    string
+		help      bool
 
 		filterField string
 		filterValue string
+		filter      string
+
+		onError         string
+		maxErrors       int
+		maxErrorPercent float64
+
+		topURLs      int
+		topStatuses  int
+		topAddresses int
+		topBy        string
+		topOther     bool
+		topCapacity  int
+		quantiles    string
+
+		stateFile string
+
+		httpTimeout  time.Duration
+		httpRetries  int
+		httpBackoff  time.Duration
+		httpToken    string
+		httpUser     string
+		httpPassword string
+		httpHeaders  = headersFlag{}
+
+		follow         bool
+		pollInterval   time.Duration
+		renderInterval time.Duration
 
 		timeFrom *time.Time
 		timeTo   *time.Time
+		timeZone *time.Location
+		bucket   time.Duration
+		keys     map[string]string
+		mode     parser.ErrorMode
+		metric   parser.TopMetric
+		qs       []float64
 
 		err error
 	)
 
-	flag.StringVar(&path, "path", "", "path to file")
-	flag.StringVar(&path, "p", "", "path to file")
+	flag.Var(&paths, "path", `path, pattern or url of logs, "-" for stdin (can be repeated)`)
+	flag.Var(&paths, "p", `path, pattern or url of logs, "-" for stdin (can be repeated)`)
+
+	flag.StringVar(&nginxConf, "nginx-conf", "", "nginx config to read access logs and their formats from")
+
+	flag.StringVar(&logFormat, "log-format", "auto", `nginx log_format string of the log lines, "json" or "auto"`)
+	flag.StringVar(&jsonKeys, "json-keys", "", "mapping of json keys to nginx variables (e.g. ts=time_iso8601,ip=remote_addr)")
+
+	flag.IntVar(&detect, "detect-lines", 10, "number of lines sampled per file to detect its format")
+
+	flag.StringVar(&from, "from", "", `keep requests at or after this time (e.g. "2024-10-22T10:00:00+03:00", "-2h", "yesterday 09:00")`)
+	flag.StringVar(&from, "f", "", "keep requests at or after this time")
+
+	flag.StringVar(&to, "to", "", "keep requests before this time, a date without a time includes the whole day")
+	flag.StringVar(&to, "t", "", "keep requests before this time")
+
+	flag.StringVar(&tz, "tz", "", `IANA time zone (e.g. "Europe/Moscow") days, time filters and times of the report are normalized to`)
 
-	flag.StringVar(&from, "from", "", "filter by time from")
-	flag.StringVar(&from, "f", "", "filter by time from")
+	flag.StringVar(&bucketStr, "bucket", "", `add a time series with buckets of this size: "minute", "5m", "hour" or "day"`)
 
-	flag.StringVar(&to, "to", "", "filter by time to")
-	flag.StringVar(&to, "t", "", "filter by time to")
+	flag.StringVar(&last, "last", "", `keep requests of the last duration (e.g. "30m", "2h", "1d")`)
 
-	flag.StringVar(&format, "format", "md", "output format")
-	flag.StringVar(&format, "fmt", "md", "output format")
+	flag.StringVar(&format, "format", "md", "output format (see the list of available formats below)")
+	flag.StringVar(&format, "fmt", "md", "output format (see the list of available formats below)")
+
+	flag.StringVar(&template, "template", "", "text/template file to render the report with (html/template for .html files), overrides -fmt")
 
 	flag.StringVar(&output, "output", "", "file for output")
 	flag.StringVar(&output, "o", "", "file for output")
@@ -72,6 +210,34 @@ func readCMDFlags() (cmdFlags, error) {
 
 	flag.StringVar(&filterField, "filter-field", "", "field for filtration")
 	flag.StringVar(&filterValue, "filter-value", "", "value for filtration")
+	flag.StringVar(&filter, "filter", "", `filter expression, e.g. 'Status >= 500 && Method in ("POST","PUT") && !(UserAgent ~ "bot")'`)
+
+	flag.StringVar(&onError, "on-error", "fail", `what to do with malformed lines: "fail" or "skip"`)
+	flag.IntVar(&maxErrors, "max-errors", 0, "fail if more malformed lines are skipped (0 for no limit)")
+	flag.Float64Var(&maxErrorPercent, "max-error-percent", 0, "fail if a bigger percent of lines is malformed (0 for no limit)")
+
+	flag.IntVar(&topURLs, "top-urls", 3, "number of resources in the report (-1 for all)")
+	flag.IntVar(&topStatuses, "top-statuses", 3, "number of response codes in the report (-1 for all)")
+	flag.IntVar(&topAddresses, "top-ips", 3, "number of addresses in the report (-1 for all)")
+	flag.StringVar(&topBy, "top-by", "count", `metric the top tables are ranked by: "count", "bytes" or "errors"`)
+	flag.BoolVar(&topOther, "top-other", false, `add an "(other)" row with the rest of every top table`)
+	flag.IntVar(&topCapacity, "top-capacity", 0, "count resources and addresses approximately, tracking at most this many of each (0 for exact counting)")
+
+	flag.StringVar(&quantiles, "quantiles", "0.5,0.75,0.9,0.95,0.99,0.999", "comma-separated quantiles of the response size to report")
+
+	flag.StringVar(&stateFile, "state", "", "file to persist read offsets and statistics for incremental runs")
+
+	flag.DurationVar(&httpTimeout, "http-timeout", 30*time.Second, "timeout for connecting to url sources, waiting for a response and for every body read")
+	flag.IntVar(&httpRetries, "http-retries", 3, "how many times failed or dropped url downloads are retried")
+	flag.DurationVar(&httpBackoff, "http-backoff", 500*time.Millisecond, "delay before the first retry, doubled on each next one")
+	flag.StringVar(&httpToken, "http-token", "", "bearer token for url sources")
+	flag.StringVar(&httpUser, "http-user", "", "user for basic auth of url sources")
+	flag.StringVar(&httpPassword, "http-password", "", "password for basic auth of url sources")
+	flag.Var(httpHeaders, "http-header", `header for url sources as "Key: Value" (can be repeated)`)
+
+	flag.BoolVar(&follow, "follow", false, "keep reading files as they grow and re-render the report")
+	flag.DurationVar(&pollInterval, "poll", time.Second, "how often followed files are checked for new lines")
+	flag.DurationVar(&renderInterval, "interval", 10*time.Second, "how often the report is re-rendered in follow mode")
 
 	flag.Parse()
 
@@ -79,28 +245,94 @@ func readCMDFlags() (cmdFlags, error) {
 		return cmdFlags{help: true}, nil
 	}
 
-	if path == "" {
+	if len(paths) == 0 && nginxConf == "" {
 		return cmdFlags{}, ErrEmptyLogPath{}
 	}
 
-	timeFrom, err = parseTime(from)
+	keys, err = parseJSONKeys(jsonKeys)
+	if err != nil {
+		return cmdFlags{}, fmt.Errorf("parse json keys %q: %w", jsonKeys, err)
+	}
+
+	mode, err = parser.ParseErrorMode(onError)
+	if err != nil {
+		return cmdFlags{}, fmt.Errorf("parse error mode %q: %w", onError, err)
+	}
+
+	metric, err = parser.ParseTopMetric(topBy)
 	if err != nil {
-		return cmdFlags{}, fmt.Errorf("parse time from %q: %w", from, err)
+		return cmdFlags{}, fmt.Errorf("parse top metric %q: %w", topBy, err)
+	}
+
+	qs, err = parseQuantiles(quantiles)
+	if err != nil {
+		return cmdFlags{}, fmt.Errorf("parse quantiles %q: %w", quantiles, err)
+	}
+
+	bucket, err = parser.ParseBucketSize(bucketStr)
+	if err != nil {
+		return cmdFlags{}, fmt.Errorf("parse bucket size %q: %w", bucketStr, err)
+	}
+
+	now := time.Now()
+
+	if tz != "" {
+		timeZone, err = time.LoadLocation(tz)
+		if err != nil {
+			return cmdFlags{}, fmt.Errorf("load time zone %q: %w", tz, err)
+		}
+
+		now = now.In(timeZone)
 	}
 
-	timeTo, err = parseTime(to)
+	timeFrom, timeTo, err = parser.ParseTimeRange(from, to, last, now)
 	if err != nil {
-		return cmdFlags{}, fmt.Errorf("parse time to %q: %w", to, err)
+		return cmdFlags{}, fmt.Errorf("parse time range: %w", err)
 	}
 
 	return cmdFlags{
-		path:        path,
+		paths:       paths,
+		nginxConf:   nginxConf,
+		logFormat:   logFormat,
+		jsonKeys:    keys,
+		detect:      detect,
 		format:      strings.ToLower(format),
+		template:    template,
 		output:      output,
 		help:        help,
 		timeFrom:    timeFrom,
 		timeTo:      timeTo,
+		timeZone:    timeZone,
+		bucket:      bucket,
 		filterField: filterField,
 		filterValue: filterValue,
+		filter:      filter,
+		errorPolicy: parser.ErrorPolicy{
+			Mode:       mode,
+			MaxErrors:  maxErrors,
+			MaxPercent: maxErrorPercent,
+		},
+		top: parser.TopOptions{
+			URLs:      topURLs,
+			Statuses:  topStatuses,
+			Addresses: topAddresses,
+			Metric:    metric,
+			Other:     topOther,
+			Capacity:  topCapacity,
+		},
+		quantiles: qs,
+		stateFile: stateFile,
+		http: parser.HTTPOptions{
+			Timeout:      httpTimeout,
+			Retries:      httpRetries,
+			RetryBackoff: httpBackoff,
+			BearerToken:  httpToken,
+			Username:     httpUser,
+			Password:     httpPassword,
+			Headers:      http.Header(httpHeaders),
+		},
+		follow:         follow,
+		pollInterval:   pollInterval,
+		renderInterval: renderInterval,
 	}, nil
 }
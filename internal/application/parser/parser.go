@@ -1,13 +1,21 @@
 package parser
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"io"
-	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
 	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/LLIEPJIOK/nginxparser/internal/render"
+	"github.com/LLIEPJIOK/nginxparser/pkg/report"
 )
 
 const possibleFilterFields = `
@@ -16,74 +24,171 @@ Possible fields for filtration:
   - RemoteUser
   - TimeLocal
   - Method
-  - Url
+  - URL
   - HTTPVersion
   - Status
   - BodyBytesSend
   - Referer
   - UserAgent
+  - any other variable of the log format (e.g. upstream_addr), any key for JSON logs
+
+Filter expressions (-filter) compare these fields, or variables of the log format written as
+name or $name (e.g. $status, $upstream_addr), with string and number literals:
+  ==, !=, <, <=, >, >=   numbers, strings and times ("2024-10-22T10:00:00Z")
+  ~, !~                  regular expressions
+  in ("a", "b")          any of the values; RemoteAddress also matches CIDRs
+  &&, ||, !, ( )         combine conditions
+  e.g. Status >= 500 && Method in ("POST","PUT") && !(UserAgent ~ "bot")
+
+Time ranges keep requests with -from <= time < -to; -last 30m is the same as -from -30m:
+  2024-10-22T10:00:00+03:00, 2024-10-22 10:00   RFC3339/ISO8601, times without a zone are in -tz or local
+  22/Oct/2024:10:00:00 +0300                    nginx $time_local
+  2024-10-22, 22/Oct/2024                       dates, -to includes the whole day
+  -2h, -1d12h, now                              relative to the current time
+  today, yesterday 09:00                        relative to the current day
 
 `
 
-func Start() error {
-	fl, err := readCMDFlags()
+func printFormats() {
+	fmt.Print("Available output formats:\n")
+
+	for _, format := range report.Formats() {
+		name := format.Name
+		if len(format.Aliases) != 0 {
+			name += " (" + strings.Join(format.Aliases, ", ") + ")"
+		}
+
+		fmt.Printf("  - %s: %s\n", name, format.Description)
+	}
+}
+
+func getReporter(fl *cmdFlags) (report.Reporter, error) {
+	if fl.template != "" {
+		reporter, err := render.NewTemplate(fl.template)
+		if err != nil {
+			return nil, fmt.Errorf("template %q: %w", fl.template, err)
+		}
+
+		return reporter, nil
+	}
+
+	reporter, err := report.Lookup(fl.format)
 	if err != nil {
-		flag.Usage()
-		fmt.Print(possibleFilterFields)
+		return nil, fmt.Errorf("format: %w", err)
+	}
 
-		return fmt.Errorf("readCMDFlags(): %w", err)
+	return reporter, nil
+}
+
+func printFilterError(err error) {
+	var filterErr parser.ErrFilter
+	if !errors.As(err, &filterErr) {
+		return
 	}
 
-	if fl.help {
-		flag.Usage()
-		fmt.Print(possibleFilterFields)
+	fmt.Fprintf(os.Stderr, "  %s\n  %s^\n", filterErr.Expr, strings.Repeat(" ", filterErr.Column-1))
+}
+
+func usage() {
+	flag.Usage()
+	printFormats()
+	fmt.Print(possibleFilterFields)
+}
+
+func writeReport(output string, reporter report.Reporter, info *domain.FileInfo) error {
+	if output == "" {
+		if err := reporter.Report(info, os.Stdout); err != nil {
+			return fmt.Errorf("write report: %w", err)
+		}
 
 		return nil
 	}
 
-	logParser := parser.New()
+	buf := &bytes.Buffer{}
+	if err := reporter.Report(info, buf); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
 
-	info, err := logParser.Parse(parser.Params{
-		Path:        fl.path,
-		From:        fl.timeFrom,
-		To:          fl.timeTo,
-		FilterField: fl.filterField,
-		FilterValue: fl.filterValue,
+	if err := os.WriteFile(output, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write file %q: %w", output, err)
+	}
+
+	return nil
+}
+
+func follow(logParser *parser.Parser, prm parser.Params, fl *cmdFlags, reporter report.Reporter) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	refresh, stopRefresh := notifyRefresh()
+	defer stopRefresh()
+
+	err := logParser.Follow(ctx, prm, refresh, func(info *domain.FileInfo) error {
+		if fl.output == "" {
+			fmt.Fprintf(os.Stdout, "\n---- %s ----\n\n", time.Now().Format(time.DateTime))
+		}
+
+		return writeReport(fl.output, reporter, info)
 	})
 	if err != nil {
-		return fmt.Errorf("parse file: %w", err)
+		printFilterError(err)
+		return fmt.Errorf("follow files: %w", err)
 	}
 
-	var wr io.Writer
+	return nil
+}
 
-	if fl.output != "" {
-		f, err := os.OpenFile(fl.output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
-		if err != nil {
-			return fmt.Errorf("open file %q: %w", fl.output, err)
-		}
+func Start() error {
+	fl, err := readCMDFlags()
+	if err != nil {
+		usage()
+		return fmt.Errorf("readCMDFlags(): %w", err)
+	}
 
-		defer func() {
-			if err := f.Close(); err != nil {
-				slog.Error(fmt.Sprintf("close file %q: %s", fl.output, err))
-			}
-		}()
+	if fl.help {
+		usage()
+		return nil
+	}
 
-		wr = f
-	} else {
-		wr = os.Stdout
+	reporter, err := getReporter(&fl)
+	if err != nil {
+		usage()
+		return err
 	}
 
-	switch fl.format {
-	case "adoc":
-		logParser.Adoc(info, wr)
+	logParser := parser.New()
 
-	case "md", "markdown":
-		logParser.Markdown(info, wr)
+	prm := parser.Params{
+		Paths:          fl.paths,
+		HTTP:           fl.http,
+		NginxConf:      fl.nginxConf,
+		LogFormat:      fl.logFormat,
+		JSONKeys:       fl.jsonKeys,
+		DetectLines:    fl.detect,
+		From:           fl.timeFrom,
+		To:             fl.timeTo,
+		FilterField:    fl.filterField,
+		FilterValue:    fl.filterValue,
+		Filter:         fl.filter,
+		ErrorPolicy:    fl.errorPolicy,
+		Top:            fl.top,
+		Quantiles:      fl.quantiles,
+		StateFile:      fl.stateFile,
+		TimeZone:       fl.timeZone,
+		Bucket:         fl.bucket,
+		PollInterval:   fl.pollInterval,
+		RenderInterval: fl.renderInterval,
+	}
 
-	default:
-		flag.Usage()
-		return NewErrFlag("format: unknown flag")
+	if fl.follow {
+		return follow(logParser, prm, &fl, reporter)
 	}
 
-	return nil
+	info, err := logParser.Parse(prm)
+	if err != nil {
+		printFilterError(err)
+		return fmt.Errorf("parse file: %w", err)
+	}
+
+	return writeReport(fl.output, reporter, info)
 }
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package parser
+
+func notifyRefresh() (refresh <-chan struct{}, stop func()) {
+	return nil, func() {}
+}
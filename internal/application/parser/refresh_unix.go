@@ -0,0 +1,38 @@
+//go:build unix
+
+package parser
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func notifyRefresh() (refresh <-chan struct{}, stop func()) {
+	signals := make(chan os.Signal, 1)
+	out := make(chan struct{})
+	done := make(chan struct{})
+
+	signal.Notify(signals, syscall.SIGUSR1)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				select {
+				case out <- struct{}{}:
+				case <-done:
+					return
+				}
+
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return out, func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -1,16 +1,36 @@
 package domain
 
-import "net/http"
-
 type FileInfo struct {
-	Paths             []string
-	TotalRequests     int
-	AvgResponseSize   int
-	ResponseSize95p   int
-	AvgResponsePerDay int
-	FrequentURLs      []URL
-	FrequentStatuses  []Status
-	FrequentAddresses []Address
+	Paths             []string       `json:"paths"`
+	TotalRequests     int            `json:"total_requests"`
+	AvgResponseSize   int            `json:"avg_response_size"`
+	ResponseSize95p   int            `json:"response_size_95p"`
+	AvgResponsePerDay int            `json:"avg_requests_per_day"`
+	FrequentURLs      []URL          `json:"frequent_urls"`
+	FrequentStatuses  []Status       `json:"frequent_statuses"`
+	FrequentAddresses []Address      `json:"frequent_addresses"`
+	Formats           []SourceFormat `json:"formats"`
+	TotalLines        int            `json:"total_lines"`
+	Malformed         []Malformed    `json:"malformed"`
+	RequestsPerDay    []DayRequests  `json:"requests_per_day"`
+	StatusClasses     []StatusClass  `json:"status_classes"`
+	SizeHistogram     []SizeBucket   `json:"response_size_histogram"`
+	ResponseSizeSum   int            `json:"response_size_sum"`
+	Statuses          []Status       `json:"statuses"`
+	Methods           []Method       `json:"methods"`
+	TopMetric         string         `json:"top_metric,omitempty"`
+	TimeZone          string         `json:"time_zone,omitempty"`
+
+	ResponseSizeQuantiles []Quantile `json:"response_size_quantiles"`
+	ResponseSizeMax       int        `json:"response_size_max"`
+
+	HeavyHitters *HeavyHitters `json:"heavy_hitters,omitempty"`
+
+	UniqueAddresses int `json:"unique_addresses"`
+	UniqueVisitors  int `json:"unique_visitors"`
+	UniqueURLs      int `json:"unique_urls"`
+
+	TimeSeries *TimeSeries `json:"time_series,omitempty"`
 }
 
 func NewFileInfo(
@@ -32,9 +52,14 @@ func NewFileInfo(
 	}
 }
 
+const OtherName = "(other)"
+
 type URL struct {
-	Name     string
-	Quantity int
+	Name     string `json:"url"`
+	Quantity int    `json:"count"`
+	Bytes    int    `json:"bytes,omitempty"`
+	Errors   int    `json:"errors,omitempty"`
+	MaxError int    `json:"max_error,omitempty"`
 }
 
 func NewURL(name string, quantity int) URL {
@@ -45,22 +70,34 @@ func NewURL(name string, quantity int) URL {
 }
 
 type Status struct {
-	Code     int
-	Name     string
-	Quantity int
+	Code     int    `json:"code,omitempty"`
+	Name     string `json:"name"`
+	Quantity int    `json:"count"`
+	Bytes    int    `json:"bytes,omitempty"`
+	Errors   int    `json:"errors,omitempty"`
 }
 
 func NewStatus(code, quantity int) Status {
 	return Status{
 		Code:     code,
-		Name:     http.StatusText(code),
+		Name:     StatusText(code),
+		Quantity: quantity,
+	}
+}
+
+func NewOtherStatus(quantity int) Status {
+	return Status{
+		Name:     OtherName,
 		Quantity: quantity,
 	}
 }
 
 type Address struct {
-	Name     string
-	Quantity int
+	Name     string `json:"address"`
+	Quantity int    `json:"count"`
+	Bytes    int    `json:"bytes,omitempty"`
+	Errors   int    `json:"errors,omitempty"`
+	MaxError int    `json:"max_error,omitempty"`
 }
 
 func NewAddress(name string, quantity int) Address {
@@ -69,3 +106,149 @@ func NewAddress(name string, quantity int) Address {
 		Quantity: quantity,
 	}
 }
+
+type SourceFormat struct {
+	Path   string `json:"path"`
+	Format string `json:"format"`
+}
+
+func NewSourceFormat(path, format string) SourceFormat {
+	return SourceFormat{
+		Path:   path,
+		Format: format,
+	}
+}
+
+type Malformed struct {
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	Count    int    `json:"count"`
+	Examples []int  `json:"example_lines"`
+}
+
+func NewMalformed(path, errType string, count int, examples []int) Malformed {
+	return Malformed{
+		Path:     path,
+		Type:     errType,
+		Count:    count,
+		Examples: examples,
+	}
+}
+
+type DayRequests struct {
+	Day             string `json:"day"`
+	Quantity        int    `json:"count"`
+	UniqueAddresses int    `json:"unique_addresses"`
+}
+
+func NewDayRequests(day string, quantity int) DayRequests {
+	return DayRequests{
+		Day:      day,
+		Quantity: quantity,
+	}
+}
+
+type StatusClass struct {
+	Class    string `json:"class"`
+	Quantity int    `json:"count"`
+}
+
+func NewStatusClass(class string, quantity int) StatusClass {
+	return StatusClass{
+		Class:    class,
+		Quantity: quantity,
+	}
+}
+
+type SizeBucket struct {
+	From     int `json:"from"`
+	To       int `json:"to"`
+	Quantity int `json:"count"`
+}
+
+func NewSizeBucket(from, to, quantity int) SizeBucket {
+	return SizeBucket{
+		From:     from,
+		To:       to,
+		Quantity: quantity,
+	}
+}
+
+type Method struct {
+	Name     string `json:"method"`
+	Quantity int    `json:"count"`
+}
+
+func NewMethod(name string, quantity int) Method {
+	return Method{
+		Name:     name,
+		Quantity: quantity,
+	}
+}
+
+type Quantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    int     `json:"value"`
+}
+
+func NewQuantile(quantile float64, value int) Quantile {
+	return Quantile{
+		Quantile: quantile,
+		Value:    value,
+	}
+}
+
+type HeavyHitters struct {
+	Capacity     int `json:"capacity"`
+	URLBound     int `json:"url_bound"`
+	AddressBound int `json:"address_bound"`
+}
+
+func NewHeavyHitters(capacity, urlBound, addressBound int) *HeavyHitters {
+	return &HeavyHitters{
+		Capacity:     capacity,
+		URLBound:     urlBound,
+		AddressBound: addressBound,
+	}
+}
+
+type TimeBucket struct {
+	Start           string  `json:"start"`
+	Quantity        int     `json:"count"`
+	Bytes           int     `json:"bytes"`
+	Errors          int     `json:"errors"`
+	ErrorRate       float64 `json:"error_rate"`
+	UniqueAddresses int     `json:"unique_addresses"`
+}
+
+func NewTimeBucket(start string, quantity, bytes, errors, uniqueAddresses int) TimeBucket {
+	bucket := TimeBucket{
+		Start:           start,
+		Quantity:        quantity,
+		Bytes:           bytes,
+		Errors:          errors,
+		UniqueAddresses: uniqueAddresses,
+	}
+
+	if quantity != 0 {
+		bucket.ErrorRate = float64(errors) / float64(quantity)
+	}
+
+	return bucket
+}
+
+type TimeSeries struct {
+	Bucket   string       `json:"bucket"`
+	Buckets  []TimeBucket `json:"buckets"`
+	Peak     TimeBucket   `json:"peak"`
+	Quietest TimeBucket   `json:"quietest"`
+}
+
+func NewTimeSeries(bucket string, buckets []TimeBucket, peak, quietest TimeBucket) *TimeSeries {
+	return &TimeSeries{
+		Bucket:   bucket,
+		Buckets:  buckets,
+		Peak:     peak,
+		Quietest: quietest,
+	}
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"net/http"
+	"sync"
+)
+
+const (
+	minStatus = 100
+	maxStatus = 599
+)
+
+var statusRegistry = struct {
+	mu    sync.RWMutex
+	names map[int]string
+}{
+	names: map[int]string{
+		444: "No Response",
+		494: "Request Header Too Large",
+		495: "SSL Certificate Error",
+		496: "SSL Certificate Required",
+		497: "HTTP Request Sent to HTTPS Port",
+		499: "Client Closed Request",
+	},
+}
+
+var statusClassNames = map[int]string{
+	1: "Informational",
+	2: "Success",
+	3: "Redirection",
+	4: "Client Error",
+	5: "Server Error",
+}
+
+func RegisterStatus(code int, name string) {
+	statusRegistry.mu.Lock()
+	defer statusRegistry.mu.Unlock()
+
+	statusRegistry.names[code] = name
+}
+
+func ValidStatus(code int) bool {
+	return code >= minStatus && code <= maxStatus
+}
+
+func StatusText(code int) string {
+	statusRegistry.mu.RLock()
+	name, ok := statusRegistry.names[code]
+	statusRegistry.mu.RUnlock()
+
+	if ok {
+		return name
+	}
+
+	if name := http.StatusText(code); name != "" {
+		return name
+	}
+
+	if class, ok := statusClassNames[code/100]; ok && ValidStatus(code) {
+		return "Unknown " + class
+	}
+
+	return "Unknown"
+}
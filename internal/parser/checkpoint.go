@@ -0,0 +1,332 @@
+package parser
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	stateVersion    = 5
+	fingerprintSize = 1024
+)
+
+type fileState struct {
+	Path            string `json:"path"`
+	Inode           uint64 `json:"inode"`
+	FingerprintSize int    `json:"fingerprint_size"`
+	Fingerprint     string `json:"fingerprint"`
+	Offset          int64  `json:"offset"`
+	Lines           int    `json:"lines"`
+}
+
+type state struct {
+	Version int         `json:"version"`
+	Filter  string      `json:"filter"`
+	Files   []fileState `json:"files"`
+	Data    dataState   `json:"data"`
+}
+
+func filterSignature(prm *Params) string {
+	var from, to string
+
+	if prm.From != nil {
+		from = prm.From.String()
+	}
+
+	if prm.To != nil {
+		to = prm.To.String()
+	}
+
+	signature := fmt.Sprintf("from=%q to=%q field=%q value=%q", from, to, prm.FilterField, prm.FilterValue)
+	if prm.Filter != "" {
+		signature += fmt.Sprintf(" filter=%q", prm.Filter)
+	}
+
+	if prm.TimeZone != nil {
+		signature += fmt.Sprintf(" tz=%q", prm.TimeZone)
+	}
+
+	if prm.Bucket > 0 {
+		signature += fmt.Sprintf(" bucket=%s", prm.Bucket)
+	}
+
+	if prm.Top.Capacity > 0 {
+		signature += fmt.Sprintf(" capacity=%d metric=%q", prm.Top.Capacity, prm.Top.metric())
+	}
+
+	return signature + formatSignature(prm)
+}
+
+func formatSignature(prm *Params) string {
+	var signature string
+
+	if prm.LogFormat != "" {
+		signature += fmt.Sprintf(" format=%q", prm.LogFormat)
+	}
+
+	if prm.NginxConf != "" {
+		signature += fmt.Sprintf(" nginx_conf=%q", prm.NginxConf)
+	}
+
+	keys := make([]string, 0, len(prm.JSONKeys))
+	for key := range prm.JSONKeys {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		signature += fmt.Sprintf(" json_key=%q:%q", key, prm.JSONKeys[key])
+	}
+
+	if prm.DetectLines > 0 {
+		signature += fmt.Sprintf(" detect=%d", prm.DetectLines)
+	}
+
+	return signature
+}
+
+func loadState(path string, prm *Params) (*state, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &state{
+			Version: stateVersion,
+			Filter:  filterSignature(prm),
+			Files:   make([]fileState, 0),
+		}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read state file %q: %w", path, err)
+	}
+
+	st := &state{}
+	if err := json.Unmarshal(content, st); err != nil {
+		return nil, NewErrState(fmt.Sprintf("decode state file %q: %s", path, err))
+	}
+
+	if st.Version != stateVersion {
+		return nil, NewErrState(fmt.Sprintf("unsupported state version %d", st.Version))
+	}
+
+	if st.Filter != filterSignature(prm) {
+		return nil, NewErrState(fmt.Sprintf("state file %q was created with other filters or log format: %s", path, st.Filter))
+	}
+
+	return st, nil
+}
+
+func saveState(path string, st *state) error {
+	content, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temporary state file: %w", err)
+	}
+
+	defer func() {
+		if err := os.Remove(tmp.Name()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Error(fmt.Sprintf("remove temporary state file: %s", err))
+		}
+	}()
+
+	if _, err := tmp.Write(content); err != nil {
+		closeResource(tmp)
+		return fmt.Errorf("write state file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close state file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename state file: %w", err)
+	}
+
+	return nil
+}
+
+func fingerprint(head []byte) string {
+	sum := sha256.Sum256(head)
+	return hex.EncodeToString(sum[:])
+}
+
+func (st *state) match(inode uint64, head []byte) int {
+	found := -1
+
+	for i, fs := range st.Files {
+		if fs.FingerprintSize == 0 || fs.FingerprintSize > len(head) ||
+			fs.Fingerprint != fingerprint(head[:fs.FingerprintSize]) {
+			continue
+		}
+
+		if inode != 0 && fs.Inode == inode {
+			return i
+		}
+
+		if found == -1 {
+			found = i
+		}
+	}
+
+	return found
+}
+
+func (st *state) update(sources []source) {
+	for _, src := range sources {
+		if src.fingerprintSize == 0 {
+			continue
+		}
+
+		fs := fileState{
+			Path:            src.name,
+			Inode:           src.inode,
+			FingerprintSize: src.fingerprintSize,
+			Fingerprint:     src.fingerprint,
+			Offset:          src.offset,
+			Lines:           src.lines,
+		}
+
+		if src.stateIndex >= 0 {
+			st.Files[src.stateIndex] = fs
+		} else {
+			st.Files = append(st.Files, fs)
+		}
+	}
+}
+
+func (src *source) head() ([]byte, error) {
+	if src.file != nil && src.compression == compressionNone {
+		head := make([]byte, fingerprintSize)
+
+		n, err := src.file.ReadAt(head, 0)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("read head of %q: %w", src.name, err)
+		}
+
+		return head[:n], nil
+	}
+
+	buf := bufio.NewReaderSize(src.reader, fingerprintSize)
+	src.reader = buf
+
+	head, err := buf.Peek(fingerprintSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("read head of %q: %w", src.name, err)
+	}
+
+	return head, nil
+}
+
+func (src *source) skip(offset int64) error {
+	if src.file != nil && src.compression == compressionNone {
+		info, err := src.file.Stat()
+		if err != nil {
+			return fmt.Errorf("stat file %q: %w", src.name, err)
+		}
+
+		if info.Size() < offset {
+			return nil
+		}
+
+		if _, err := src.file.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("seek file %q: %w", src.name, err)
+		}
+
+		src.reader = src.file
+		src.offset = offset
+
+		return nil
+	}
+
+	skipped, err := io.CopyN(io.Discard, src.reader, offset)
+	src.offset = skipped
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("skip %d bytes of %q: %w", offset, src.name, err)
+	}
+
+	return nil
+}
+
+func (st *state) resume(src *source) error {
+	head, err := src.head()
+	if err != nil {
+		return err
+	}
+
+	if src.file != nil {
+		if info, err := src.file.Stat(); err == nil {
+			src.inode = fileID(info)
+		}
+	}
+
+	src.fingerprintSize = len(head)
+	src.fingerprint = fingerprint(head)
+	src.holdPartial = src.file != nil && src.compression == compressionNone
+
+	src.stateIndex = st.match(src.inode, head)
+	if src.stateIndex == -1 {
+		return nil
+	}
+
+	fs := st.Files[src.stateIndex]
+
+	if err := src.skip(fs.Offset); err != nil {
+		return err
+	}
+
+	if src.offset == fs.Offset {
+		src.lines = fs.Lines
+	}
+
+	return nil
+}
+
+func (p *Parser) resumeSources(prm *Params, sources []source, parseData *data) (*state, error) {
+	if prm.StateFile == "" {
+		return nil, nil
+	}
+
+	st, err := loadState(prm.StateFile, prm)
+	if err != nil {
+		return nil, err
+	}
+
+	parseData.restore(&st.Data)
+
+	for i := range sources {
+		if err := st.resume(&sources[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return st, nil
+}
+
+func (p *Parser) checkpoint(prm *Params, st *state, sources []source, parseData *data) error {
+	if st == nil {
+		return nil
+	}
+
+	st.update(sources)
+	st.Data = parseData.state()
+
+	if err := saveState(prm.StateFile, st); err != nil {
+		return fmt.Errorf("saveState(%q): %w", prm.StateFile, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithStateFile(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "access.log")
+	prm := parser.Params{
+		Paths:     []string{filepath.Join(dir, "access.log*")},
+		StateFile: filepath.Join(dir, "state.json"),
+	}
+
+	parse := func() *domain.FileInfo {
+		data, err := parser.New().Parse(prm)
+		require.NoError(t, err, "logs must be parsed")
+
+		return data
+	}
+
+	appendFile(t, logPath, logLine("10.0.0.1", "/a", 100)+logLine("10.0.0.2", "/b", 200))
+
+	data := parse()
+	assert.Equal(t, 2, data.TotalRequests)
+
+	data = parse()
+	assert.Equal(t, 2, data.TotalRequests, "nothing new must be read")
+
+	appendFile(t, logPath, logLine("10.0.0.3", "/c", 300))
+
+	data = parse()
+	assert.Equal(t, 3, data.TotalRequests)
+	assert.Equal(t, 3, data.TotalLines)
+
+	require.NoError(t, os.Rename(logPath, logPath+".1"), "log must be rotated")
+	appendFile(t, logPath, logLine("10.0.0.4", "/d", 400))
+
+	data = parse()
+	assert.Equal(t, 4, data.TotalRequests)
+
+	rotated, err := os.ReadFile(logPath + ".1")
+	require.NoError(t, err, "rotated log must be read")
+	require.NoError(t, os.WriteFile(logPath+".1.gz", gzipBytes(t, string(rotated)), 0o600), "log must be compressed")
+	require.NoError(t, os.Remove(logPath+".1"), "rotated log must be removed")
+
+	data = parse()
+	assert.Equal(t, 4, data.TotalRequests, "compressed rotated log must not be counted twice")
+	assert.Equal(t, 250, data.AvgResponseSize)
+	assert.Equal(t, []domain.URL{
+		domain.NewURL("/a", 1),
+		domain.NewURL("/b", 1),
+		domain.NewURL("/c", 1),
+	}, data.FrequentURLs)
+}
+
+func TestParseWithStateFilePartialLine(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "access.log")
+	prm := parser.Params{
+		Paths:     []string{logPath},
+		StateFile: filepath.Join(dir, "state.json"),
+	}
+
+	line := logLine("10.0.0.2", "/b", 200)
+
+	appendFile(t, logPath, logLine("10.0.0.1", "/a", 100)+line[:20])
+
+	data, err := parser.New().Parse(prm)
+	require.NoError(t, err, "logs must be parsed")
+	assert.Equal(t, 1, data.TotalRequests)
+	assert.Equal(t, 1, data.TotalLines, "partial line must be left for the next run")
+
+	appendFile(t, logPath, line[20:]+logLine("10.0.0.3", "/c", 300))
+
+	data, err = parser.New().Parse(prm)
+	require.NoError(t, err, "logs must be parsed")
+	assert.Equal(t, 3, data.TotalRequests)
+	assert.Equal(t, 3, data.TotalLines)
+	assert.Empty(t, data.Malformed)
+}
+
+func TestParseWithStateFileFilterMismatch(t *testing.T) {
+	dir := t.TempDir()
+	appendFile(t, filepath.Join(dir, "access.log"), logLine("10.0.0.1", "/a", 100))
+
+	prm := parser.Params{
+		Paths:     []string{filepath.Join(dir, "access.log")},
+		StateFile: filepath.Join(dir, "state.json"),
+	}
+
+	_, err := parser.New().Parse(prm)
+	require.NoError(t, err, "log must be parsed")
+
+	prm.FilterField = "Method"
+	prm.FilterValue = "POST"
+
+	_, err = parser.New().Parse(prm)
+	require.ErrorAs(t, err, &parser.ErrState{})
+}
+
+func TestParseWithStateFileFormatMismatch(t *testing.T) {
+	dir := t.TempDir()
+	appendFile(t, filepath.Join(dir, "access.log"), logLine("10.0.0.1", "/a", 100))
+
+	prm := parser.Params{
+		Paths:     []string{filepath.Join(dir, "access.log")},
+		StateFile: filepath.Join(dir, "state.json"),
+	}
+
+	_, err := parser.New().Parse(prm)
+	require.NoError(t, err, "log must be parsed")
+
+	prm.LogFormat = "$remote_addr $remote_user"
+
+	_, err = parser.New().Parse(prm)
+	require.ErrorAs(t, err, &parser.ErrState{}, "state of other log format must be rejected")
+
+	prm.LogFormat = ""
+	prm.JSONKeys = map[string]string{"status": "code"}
+
+	_, err = parser.New().Parse(prm)
+	require.ErrorAs(t, err, &parser.ErrState{}, "state of other JSON keys must be rejected")
+}
+
+func TestParseWithStateFileHeavyHitters(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "access.log")
+	prm := parser.Params{
+		Paths:     []string{logPath},
+		StateFile: filepath.Join(dir, "state.json"),
+		Top: parser.TopOptions{
+			URLs:     -1,
+			Capacity: 10,
+		},
+	}
+
+	appendFile(t, logPath, logLine("10.0.0.1", "/a", 100)+logLine("10.0.0.2", "/b", 200))
+
+	_, err := parser.New().Parse(prm)
+	require.NoError(t, err, "logs must be parsed")
+
+	appendFile(t, logPath, logLine("10.0.0.3", "/a", 300))
+
+	data, err := parser.New().Parse(prm)
+	require.NoError(t, err, "logs must be parsed")
+
+	assert.Equal(t, []domain.URL{
+		domain.NewURL("/a", 2),
+		domain.NewURL("/b", 1),
+	}, data.FrequentURLs)
+
+	prm.Top.Capacity = 5
+
+	_, err = parser.New().Parse(prm)
+	require.ErrorAs(t, err, &parser.ErrState{}, "state of other capacity must be rejected")
+}
@@ -0,0 +1,352 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const combinedFormatName = "combined"
+
+type NginxLog struct {
+	Path       string
+	FormatName string
+	Format     string
+	Escape     string
+}
+
+type nginxLogFormat struct {
+	format string
+	escape string
+}
+
+type confToken struct {
+	text   string
+	quoted bool
+	line   int
+}
+
+type confDirective struct {
+	name string
+	args []confToken
+	line int
+	file string
+}
+
+func isConfSpecial(c byte) bool {
+	return c == ';' || c == '{' || c == '}'
+}
+
+func isConfSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func readQuoted(content string, pos, lineNumber int) (text string, end, lines int, err error) {
+	quote := content[pos]
+
+	var sb strings.Builder
+
+	for i := pos + 1; i < len(content); i++ {
+		c := content[i]
+
+		switch {
+		case c == '\\' && i+1 < len(content):
+			i++
+
+			switch content[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			default:
+				sb.WriteByte(content[i])
+			}
+
+		case c == quote:
+			return sb.String(), i + 1, lines, nil
+
+		default:
+			if c == '\n' {
+				lines++
+			}
+
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", 0, 0, NewErrNginxConf(fmt.Sprintf("line %d: unterminated quoted string", lineNumber))
+}
+
+func tokenizeConf(content string) ([]confToken, error) {
+	tokens := make([]confToken, 0)
+	lineNumber := 1
+
+	for pos := 0; pos < len(content); {
+		c := content[pos]
+
+		switch {
+		case c == '\n':
+			lineNumber++
+			pos++
+
+		case isConfSpace(c):
+			pos++
+
+		case c == '#':
+			end := strings.IndexByte(content[pos:], '\n')
+			if end == -1 {
+				pos = len(content)
+			} else {
+				pos += end
+			}
+
+		case isConfSpecial(c):
+			tokens = append(tokens, confToken{text: string(c), line: lineNumber})
+			pos++
+
+		case c == '"' || c == '\'':
+			text, end, lines, err := readQuoted(content, pos, lineNumber)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, confToken{text: text, quoted: true, line: lineNumber})
+			lineNumber += lines
+			pos = end
+
+		default:
+			end := pos
+			for end < len(content) && !isConfSpace(content[end]) && !isConfSpecial(content[end]) {
+				end++
+			}
+
+			tokens = append(tokens, confToken{text: content[pos:end], line: lineNumber})
+			pos = end
+		}
+	}
+
+	return tokens, nil
+}
+
+type confReader struct {
+	root       string
+	formats    map[string]nginxLogFormat
+	directives []confDirective
+	visited    map[string]bool
+}
+
+func (r *confReader) readFile(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("get absolute path of %q: %w", path, err)
+	}
+
+	if r.visited[absPath] {
+		return NewErrNginxConf(fmt.Sprintf("%s: recursive include", path))
+	}
+
+	r.visited[absPath] = true
+	defer delete(r.visited, absPath)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read nginx config %q: %w", path, err)
+	}
+
+	tokens, err := tokenizeConf(string(content))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return r.readDirectives(path, tokens)
+}
+
+func (r *confReader) readDirectives(path string, tokens []confToken) error {
+	depth := 0
+	args := make([]confToken, 0)
+
+	for _, token := range tokens {
+		if token.quoted || !isConfSpecial(token.text[0]) || len(token.text) > 1 {
+			args = append(args, token)
+			continue
+		}
+
+		switch token.text {
+		case "{":
+			depth++
+
+		case "}":
+			depth--
+			if depth < 0 || len(args) != 0 {
+				return NewErrNginxConf(fmt.Sprintf("%s:%d: unexpected \"}\"", path, token.line))
+			}
+
+		case ";":
+			if len(args) == 0 {
+				return NewErrNginxConf(fmt.Sprintf("%s:%d: unexpected \";\"", path, token.line))
+			}
+
+			if err := r.handleDirective(confDirective{
+				name: args[0].text,
+				args: args[1:],
+				line: args[0].line,
+				file: path,
+			}); err != nil {
+				return err
+			}
+		}
+
+		args = make([]confToken, 0)
+	}
+
+	if depth != 0 || len(args) != 0 {
+		return NewErrNginxConf(fmt.Sprintf("%s: unexpected end of file", path))
+	}
+
+	return nil
+}
+
+func (r *confReader) include(dir confDirective) error {
+	if len(dir.args) != 1 {
+		return NewErrNginxConf(fmt.Sprintf("%s:%d: invalid number of arguments in \"include\"", dir.file, dir.line))
+	}
+
+	pattern := dir.args[0].text
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(r.root, pattern)
+	}
+
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("find files for include %q: %w", pattern, err)
+	}
+
+	for _, path := range paths {
+		if err := r.readFile(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *confReader) logFormat(dir confDirective) error {
+	if len(dir.args) < 2 {
+		return NewErrNginxConf(fmt.Sprintf("%s:%d: invalid number of arguments in \"log_format\"", dir.file, dir.line))
+	}
+
+	name := dir.args[0].text
+	parts := dir.args[1:]
+	escape := "default"
+
+	if value, ok := strings.CutPrefix(parts[0].text, "escape="); ok && !parts[0].quoted {
+		escape = value
+		parts = parts[1:]
+	}
+
+	var format strings.Builder
+	for _, part := range parts {
+		format.WriteString(part.text)
+	}
+
+	r.formats[name] = nginxLogFormat{
+		format: format.String(),
+		escape: escape,
+	}
+
+	return nil
+}
+
+func (r *confReader) handleDirective(dir confDirective) error {
+	switch dir.name {
+	case "include":
+		return r.include(dir)
+
+	case "log_format":
+		return r.logFormat(dir)
+
+	case "access_log":
+		r.directives = append(r.directives, dir)
+	}
+
+	return nil
+}
+
+var confVariableRegexp = regexp.MustCompile(`\$(\{\w+\}|\w+)`)
+
+func (r *confReader) accessLog(dir confDirective) (NginxLog, bool, error) {
+	if len(dir.args) == 0 {
+		return NginxLog{}, false, NewErrNginxConf(
+			fmt.Sprintf("%s:%d: invalid number of arguments in \"access_log\"", dir.file, dir.line),
+		)
+	}
+
+	path := dir.args[0].text
+	if path == "off" || strings.HasPrefix(path, "syslog:") || strings.HasPrefix(path, "memory:") {
+		return NginxLog{}, false, nil
+	}
+
+	path = confVariableRegexp.ReplaceAllString(path, "*")
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.root, path)
+	}
+
+	formatName := combinedFormatName
+	if len(dir.args) > 1 && !strings.Contains(dir.args[1].text, "=") {
+		formatName = dir.args[1].text
+	}
+
+	format, ok := r.formats[formatName]
+	if !ok {
+		return NginxLog{}, false, NewErrNginxConf(
+			fmt.Sprintf("%s:%d: unknown log format %q", dir.file, dir.line, formatName),
+		)
+	}
+
+	return NginxLog{
+		Path:       path,
+		FormatName: formatName,
+		Format:     format.format,
+		Escape:     format.escape,
+	}, true, nil
+}
+
+func ReadNginxConf(path string) ([]NginxLog, error) {
+	r := &confReader{
+		root: filepath.Dir(path),
+		formats: map[string]nginxLogFormat{
+			combinedFormatName: {
+				format: CombinedFormat,
+				escape: "default",
+			},
+		},
+		directives: make([]confDirective, 0),
+		visited:    make(map[string]bool),
+	}
+
+	if err := r.readFile(path); err != nil {
+		return nil, err
+	}
+
+	logs := make([]NginxLog, 0, len(r.directives))
+	seen := make(map[NginxLog]bool)
+
+	for _, dir := range r.directives {
+		accessLog, ok, err := r.accessLog(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok && !seen[accessLog] {
+			seen[accessLog] = true
+
+			logs = append(logs, accessLog)
+		}
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,140 @@
+package parser_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	require.NoError(t, err, "dir must be created")
+
+	err = os.WriteFile(path, []byte(content), 0o600)
+	require.NoError(t, err, "file must be written")
+}
+
+const mainConf = `
+user nginx;
+
+http {
+    log_format upstream '$remote_addr - $remote_user [$time_local] "$request" '
+                        '$status $body_bytes_sent "$http_referer" "$http_user_agent" $upstream_addr';
+
+    log_format json escape=json '{"ip":"$remote_addr","ts":"$time_iso8601",'
+                                '"request":"$request","status":$status,"size":$body_bytes_sent}';
+
+    access_log %[1]s/logs/access.log;
+
+    include conf.d/*.conf;
+}
+`
+
+const serverConf = `
+server {
+    listen 80; # comment with "quotes" and ;
+    access_log logs/api.log upstream buffer=32k;
+
+    location /v2 {
+        access_log logs/json.log json;
+    }
+
+    location /static {
+        access_log off;
+    }
+
+    location /health {
+        access_log syslog:server=unix:/dev/log;
+    }
+}
+`
+
+func TestReadNginxConf(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "nginx.conf"), fmt.Sprintf(mainConf, dir))
+	writeFile(t, filepath.Join(dir, "conf.d", "api.conf"), serverConf)
+
+	logs, err := parser.ReadNginxConf(filepath.Join(dir, "nginx.conf"))
+	require.NoError(t, err, "config must be read")
+
+	require.Len(t, logs, 3)
+	assert.Equal(t, filepath.Join(dir, "logs", "access.log"), logs[0].Path)
+	assert.Equal(t, "combined", logs[0].FormatName)
+	assert.Equal(t, parser.CombinedFormat, logs[0].Format)
+	assert.Equal(t, filepath.Join(dir, "logs", "api.log"), logs[1].Path)
+	assert.Equal(t, "upstream", logs[1].FormatName)
+	assert.Equal(t,
+		`$remote_addr - $remote_user [$time_local] "$request" `+
+			`$status $body_bytes_sent "$http_referer" "$http_user_agent" $upstream_addr`,
+		logs[1].Format,
+	)
+	assert.Equal(t, "json", logs[2].Escape)
+}
+
+func TestReadNginxConfError(t *testing.T) {
+	tt := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "unknown format",
+			content: "http { access_log /var/log/access.log main; }",
+		},
+		{
+			name:    "unclosed block",
+			content: "http { server {",
+		},
+		{
+			name:    "unterminated string",
+			content: "http { log_format main '$remote_addr; }",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "nginx.conf")
+			writeFile(t, path, tc.content)
+
+			_, err := parser.ReadNginxConf(path)
+			require.ErrorAs(t, err, &parser.ErrNginxConf{})
+		})
+	}
+}
+
+func TestParseNginxConf(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "nginx.conf"), fmt.Sprintf(mainConf, dir))
+	writeFile(t, filepath.Join(dir, "conf.d", "api.conf"), serverConf)
+	writeFile(t, filepath.Join(dir, "logs", "access.log"),
+		`10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /index.html HTTP/1.1" 200 100 "-" "curl/8.0"`)
+	writeFile(t, filepath.Join(dir, "logs", "api.log"),
+		`10.0.0.2 - - [22/Oct/2024:09:48:45 +0000] "GET /api HTTP/1.1" 200 300 "-" "curl/8.0" 127.0.0.1:8080`)
+	writeFile(t, filepath.Join(dir, "logs", "json.log"),
+		`{"ip":"10.0.0.3","ts":"2024-10-22T09:48:45+00:00","request":"GET /v2 HTTP/1.1","status":200,"size":200}`)
+
+	logParser := parser.New()
+
+	data, err := logParser.Parse(parser.Params{
+		NginxConf: filepath.Join(dir, "nginx.conf"),
+	})
+	require.NoError(t, err, "logs must be parsed")
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "logs", "access.log"),
+		filepath.Join(dir, "logs", "api.log"),
+		filepath.Join(dir, "logs", "json.log"),
+	}, data.Paths)
+	assert.Equal(t, 3, data.TotalRequests)
+	assert.Equal(t, 200, data.AvgResponseSize)
+	assert.Equal(t, []domain.URL{
+		domain.NewURL("/api", 1),
+		domain.NewURL("/index.html", 1),
+		domain.NewURL("/v2", 1),
+	}, data.FrequentURLs)
+}
@@ -2,6 +2,9 @@ package parser
 
 import (
 	"sync"
+	"time"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
 )
 
 const timeLayout = "02/Jan/2006"
@@ -9,38 +12,285 @@ const timeLayout = "02/Jan/2006"
 type data struct {
 	mu             *sync.RWMutex
 	paths          []string
+	formats        []string
 	totalRequests  int
-	urls           map[string]int
-	statuses       map[int]int
+	urls           map[string]*counter
+	urlSketch      *spaceSaving
+	methods        map[string]int
+	statuses       map[int]*counter
 	sizeSum        int
-	sizeSlice      []int
-	addresses      map[string]int
+	sizes          *sizeSketch
+	addresses      map[string]*counter
+	addressSketch  *spaceSaving
 	requestsPerDay map[string]int
+	dayAddresses   map[string]*hyperLogLog
+	uniqueAddrs    *hyperLogLog
+	uniqueVisitors *hyperLogLog
+	uniqueURLs     *hyperLogLog
+	totalLines     int
+	malformedCount int
+	malformed      map[malformedKey]*malformedLines
+	top            TopOptions
+	quantiles      []float64
+	timeZone       string
+	bucket         time.Duration
+	location       *time.Location
+	timeSeries     map[string]*timeBucket
+	seriesFirst    time.Time
+	seriesLast     time.Time
 }
 
-func newData() data {
-	return data{
+func newData(top TopOptions) data {
+	parseData := data{
 		mu:             &sync.RWMutex{},
 		paths:          make([]string, 0),
+		formats:        make([]string, 0),
 		totalRequests:  0,
-		urls:           make(map[string]int),
-		statuses:       make(map[int]int),
+		urls:           make(map[string]*counter),
+		methods:        make(map[string]int),
+		statuses:       make(map[int]*counter),
 		sizeSum:        0,
-		sizeSlice:      make([]int, 0),
-		addresses:      make(map[string]int),
+		sizes:          newSizeSketch(),
+		addresses:      make(map[string]*counter),
 		requestsPerDay: make(map[string]int),
+		dayAddresses:   make(map[string]*hyperLogLog),
+		uniqueAddrs:    newHyperLogLog(hllPrecision),
+		uniqueVisitors: newHyperLogLog(hllPrecision),
+		uniqueURLs:     newHyperLogLog(hllPrecision),
+		malformed:      make(map[malformedKey]*malformedLines),
+		top:            top,
+		location:       time.UTC,
+		timeSeries:     make(map[string]*timeBucket),
+	}
+
+	if top.Capacity > 0 {
+		parseData.urlSketch = newSpaceSaving(top.Capacity, top.metric())
+		parseData.addressSketch = newSpaceSaving(top.Capacity, top.metric())
+	}
+
+	return parseData
+}
+
+func countHeavy(counters map[string]*counter, sketch *spaceSaving, key string, logEntry *log) {
+	if sketch != nil {
+		sketch.add(key, logEntry)
+		return
 	}
+
+	countKey(counters, key, logEntry)
 }
 
-func (d *data) processLog(logEntry *log) {
+func (d *data) countDay(logEntry *log) {
+	if logEntry.TimeLocal.IsZero() {
+		return
+	}
+
+	day := logEntry.TimeLocal.Format(timeLayout)
+	d.requestsPerDay[day]++
+
+	addresses, ok := d.dayAddresses[day]
+	if !ok {
+		addresses = newHyperLogLog(hllDayPrecision)
+		d.dayAddresses[day] = addresses
+	}
+
+	addresses.add(logEntry.RemoteAddress)
+}
+
+func (d *data) processLog(logEntry *log) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
 	d.totalRequests++
-	d.urls[logEntry.URL]++
-	d.statuses[logEntry.Status]++
+	countHeavy(d.urls, d.urlSketch, logEntry.URL, logEntry)
+	d.methods[logEntry.Method]++
+	countKey(d.statuses, logEntry.Status, logEntry)
 	d.sizeSum += logEntry.BodyBytesSend
-	d.sizeSlice = append(d.sizeSlice, logEntry.BodyBytesSend)
-	d.addresses[logEntry.RemoteAddress]++
-	d.requestsPerDay[logEntry.TimeLocal.Format(timeLayout)]++
+	d.sizes.add(logEntry.BodyBytesSend)
+	countHeavy(d.addresses, d.addressSketch, logEntry.RemoteAddress, logEntry)
+	d.countDay(logEntry)
+	d.uniqueAddrs.add(logEntry.RemoteAddress)
+	d.uniqueVisitors.add(logEntry.RemoteAddress, logEntry.UserAgent)
+	d.uniqueURLs.add(logEntry.URL)
+
+	if d.bucket > 0 {
+		return d.countBucket(logEntry)
+	}
+
+	return nil
+}
+
+func (d *data) addSources(sources []source) {
+	for _, src := range sources {
+		d.addSource(src.name, src.formatName)
+	}
+}
+
+func (d *data) addSource(path, formatName string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, known := range d.paths {
+		if known == path {
+			d.formats[i] = formatName
+			return
+		}
+	}
+
+	d.paths = append(d.paths, path)
+	d.formats = append(d.formats, formatName)
+}
+
+func (d *data) fileInfo() *domain.FileInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return dataToFileInfo(d)
+}
+
+func (d *data) addLines(count int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.totalLines += count
+}
+
+func (d *data) addMalformed(path, errType string, lineNumber int) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := malformedKey{path: path, errType: errType}
+
+	lines, ok := d.malformed[key]
+	if !ok {
+		lines = &malformedLines{}
+		d.malformed[key] = lines
+	}
+
+	lines.add(lineNumber)
+	d.malformedCount++
+
+	return d.malformedCount
+}
+
+type malformedState struct {
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	Count    int    `json:"count"`
+	Examples []int  `json:"examples"`
+}
+
+type dataState struct {
+	Paths          []string                `json:"paths"`
+	Formats        []string                `json:"formats"`
+	TotalRequests  int                     `json:"total_requests"`
+	URLs           map[string]*counter     `json:"urls"`
+	URLSketch      *spaceSaving            `json:"url_sketch,omitempty"`
+	Methods        map[string]int          `json:"methods"`
+	Statuses       map[int]*counter        `json:"statuses"`
+	SizeSum        int                     `json:"size_sum"`
+	Sizes          *sizeSketch             `json:"sizes"`
+	Addresses      map[string]*counter     `json:"addresses"`
+	AddressSketch  *spaceSaving            `json:"address_sketch,omitempty"`
+	RequestsPerDay map[string]int          `json:"requests_per_day"`
+	DayAddresses   map[string]*hyperLogLog `json:"day_addresses"`
+	UniqueAddrs    *hyperLogLog            `json:"unique_addresses"`
+	UniqueVisitors *hyperLogLog            `json:"unique_visitors"`
+	UniqueURLs     *hyperLogLog            `json:"unique_urls"`
+	TotalLines     int                     `json:"total_lines"`
+	MalformedCount int                     `json:"malformed_count"`
+	Malformed      []malformedState        `json:"malformed"`
+	TimeSeries     map[string]*timeBucket  `json:"time_series,omitempty"`
+}
+
+func (d *data) state() dataState {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	malformed := make([]malformedState, 0, len(d.malformed))
+	for key, lines := range d.malformed {
+		malformed = append(malformed, malformedState{
+			Path:     key.path,
+			Type:     key.errType,
+			Count:    lines.count,
+			Examples: lines.examples,
+		})
+	}
+
+	return dataState{
+		Paths:          d.paths,
+		Formats:        d.formats,
+		TotalRequests:  d.totalRequests,
+		URLs:           d.urls,
+		URLSketch:      d.urlSketch,
+		Methods:        d.methods,
+		Statuses:       d.statuses,
+		SizeSum:        d.sizeSum,
+		Sizes:          d.sizes,
+		Addresses:      d.addresses,
+		AddressSketch:  d.addressSketch,
+		RequestsPerDay: d.requestsPerDay,
+		DayAddresses:   d.dayAddresses,
+		UniqueAddrs:    d.uniqueAddrs,
+		UniqueVisitors: d.uniqueVisitors,
+		UniqueURLs:     d.uniqueURLs,
+		TotalLines:     d.totalLines,
+		MalformedCount: d.malformedCount,
+		Malformed:      malformed,
+		TimeSeries:     d.timeSeries,
+	}
+}
+
+func copyMap[K comparable, V any](dst, src map[K]V) {
+	for key, value := range src {
+		dst[key] = value
+	}
+}
+
+func (d *data) restore(st *dataState) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.paths = append(d.paths, st.Paths...)
+	d.formats = append(d.formats, st.Formats...)
+	d.totalRequests = st.TotalRequests
+	copyMap(d.urls, st.URLs)
+	copyMap(d.methods, st.Methods)
+	copyMap(d.statuses, st.Statuses)
+	d.sizeSum = st.SizeSum
+	if st.Sizes != nil {
+		d.sizes.merge(st.Sizes)
+	}
+
+	copyMap(d.addresses, st.Addresses)
+	restoreSketch(d.urlSketch, st.URLSketch)
+	restoreSketch(d.addressSketch, st.AddressSketch)
+	copyMap(d.requestsPerDay, st.RequestsPerDay)
+	copyMap(d.dayAddresses, st.DayAddresses)
+	copyMap(d.timeSeries, st.TimeSeries)
+	d.restoreSeriesRange()
+	mergeHyperLogLog(d.uniqueAddrs, st.UniqueAddrs)
+	mergeHyperLogLog(d.uniqueVisitors, st.UniqueVisitors)
+	mergeHyperLogLog(d.uniqueURLs, st.UniqueURLs)
+	d.totalLines = st.TotalLines
+	d.malformedCount = st.MalformedCount
+
+	for _, malformed := range st.Malformed {
+		d.malformed[malformedKey{path: malformed.Path, errType: malformed.Type}] = &malformedLines{
+			count:    malformed.Count,
+			examples: malformed.Examples,
+		}
+	}
+}
+
+func restoreSketch(sketch, saved *spaceSaving) {
+	if sketch != nil && saved != nil {
+		sketch.restore(saved)
+	}
+}
+
+func mergeHyperLogLog(sketch, saved *hyperLogLog) {
+	if saved != nil {
+		sketch.merge(saved)
+	}
 }
@@ -0,0 +1,147 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	compressionNone  = ""
+	compressionGzip  = "gzip"
+	compressionBzip2 = "bzip2"
+	compressionZstd  = "zstd"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+const sniffLength = 4
+
+var contentEncodings = map[string]string{
+	"gzip":   compressionGzip,
+	"x-gzip": compressionGzip,
+	"bzip2":  compressionBzip2,
+	"zstd":   compressionZstd,
+}
+
+var contentTypes = map[string]string{
+	"application/gzip":    compressionGzip,
+	"application/x-gzip":  compressionGzip,
+	"application/x-bzip2": compressionBzip2,
+	"application/zstd":    compressionZstd,
+}
+
+func httpCompression(header http.Header) string {
+	encoding := strings.ToLower(strings.TrimSpace(header.Get("Content-Encoding")))
+	if compression, ok := contentEncodings[encoding]; ok {
+		return compression
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return compressionNone
+	}
+
+	return contentTypes[mediaType]
+}
+
+func sniffCompression(reader *bufio.Reader) (string, error) {
+	magic, err := reader.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return compressionNone, fmt.Errorf("peek magic bytes: %w", err)
+	}
+
+	return magicCompression(magic), nil
+}
+
+func magicCompression(magic []byte) string {
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return compressionGzip
+
+	case bytes.HasPrefix(magic, bzip2Magic):
+		return compressionBzip2
+
+	case bytes.HasPrefix(magic, zstdMagic):
+		return compressionZstd
+	}
+
+	return compressionNone
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+func decompress(buf *bufio.Reader, compression string) (io.Reader, io.Closer, error) {
+	switch compression {
+	case compressionGzip:
+		gz, err := gzip.NewReader(buf)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open gzip stream: %w", err)
+		}
+
+		return gz, gz, nil
+
+	case compressionBzip2:
+		return bzip2.NewReader(buf), nil, nil
+
+	case compressionZstd:
+		zr, err := zstd.NewReader(buf)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open zstd stream: %w", err)
+		}
+
+		return zr, closerFunc(func() error {
+			zr.Close()
+			return nil
+		}), nil
+	}
+
+	return buf, nil, nil
+}
+
+func (src *source) decompress() error {
+	buf := bufio.NewReader(src.reader)
+
+	if src.compression == compressionNone {
+		sniffed, err := sniffCompression(buf)
+		if err != nil {
+			return fmt.Errorf("sniff compression of %q: %w", src.name, err)
+		}
+
+		src.compression = sniffed
+	}
+
+	reader, closer, err := decompress(buf, src.compression)
+	if err != nil {
+		return fmt.Errorf("decompress %q: %w", src.name, err)
+	}
+
+	src.reader = reader
+
+	if closer != nil {
+		base := src.closer
+		src.closer = closerFunc(func() error {
+			closeResource(closer)
+			return base.Close()
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package parser_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bzip2Content = "QlpoOTFBWSZTWZWN4WoAABjfgEAQUAv+cALAxAoIBBYAIABUU0aZBBhGAgiZT8qMhkNNDRoPC1aUYdC/sMs" +
+	"skcdigWvtt3l08FRolptyaUELrNITQVTAg7tibj9wGRyzR7+0j8XckU4UJCVjeFqA"
+
+func logLine(address, url string, size int) string {
+	return fmt.Sprintf(`%s - - [22/Oct/2024:09:48:45 +0000] "GET %s HTTP/1.1" 200 %d "-" "curl/8.0"`+"\n",
+		address, url, size)
+}
+
+func gzipBytes(t *testing.T, content string) []byte {
+	buf := &bytes.Buffer{}
+
+	gz := gzip.NewWriter(buf)
+	_, err := gz.Write([]byte(content))
+	require.NoError(t, err, "content must be compressed")
+	require.NoError(t, gz.Close(), "gzip writer must be closed")
+
+	return buf.Bytes()
+}
+
+func zstdBytes(t *testing.T, content string) []byte {
+	enc, err := zstd.NewWriter(nil)
+	require.NoError(t, err, "zstd writer must be created")
+
+	defer enc.Close()
+
+	return enc.EncodeAll([]byte(content), nil)
+}
+
+func TestParseCompressedFiles(t *testing.T) {
+	bz2, err := base64.StdEncoding.DecodeString(bzip2Content)
+	require.NoError(t, err, "bzip2 fixture must be decoded")
+
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"access.log":       []byte(logLine("10.0.0.1", "/a", 100)),
+		"access.log.1":     []byte(logLine("10.0.0.2", "/b", 200)),
+		"access.log.2.gz":  gzipBytes(t, logLine("10.0.0.4", "/d", 400)+logLine("10.0.0.4", "/d", 400)),
+		"access.log.3.bz2": bz2,
+		"access.log.4.zst": zstdBytes(t, logLine("10.0.0.5", "/e", 500)),
+	}
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0o600), "file must be written")
+	}
+
+	logParser := parser.New()
+
+	data, err := logParser.Parse(parser.Params{
+		Paths: []string{filepath.Join(dir, "access.log*")},
+	})
+	require.NoError(t, err, "compressed files must be parsed")
+
+	assert.Equal(t, 6, data.TotalRequests)
+	assert.Equal(t, 316, data.AvgResponseSize)
+}
+
+func TestParseCompressedURL(t *testing.T) {
+	tt := []struct {
+		name        string
+		contentType string
+		body        func(t *testing.T) []byte
+	}{
+		{
+			name:        "gzip content type",
+			contentType: "application/gzip",
+			body: func(t *testing.T) []byte {
+				return gzipBytes(t, logLine("10.0.0.1", "/a", 100))
+			},
+		},
+		{
+			name:        "zstd without content type",
+			contentType: "application/octet-stream",
+			body: func(t *testing.T) []byte {
+				return zstdBytes(t, logLine("10.0.0.1", "/a", 100))
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			body := tc.body(t)
+
+			server := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.Header().Set("Content-Type", tc.contentType)
+					_, _ = w.Write(body)
+				}),
+			)
+			defer server.Close()
+
+			logParser := parser.New()
+
+			data, err := logParser.Parse(parser.Params{
+				Paths: []string{server.URL},
+			})
+			require.NoError(t, err, "compressed body must be parsed")
+
+			assert.Equal(t, 1, data.TotalRequests)
+			assert.Equal(t, 100, data.AvgResponseSize)
+		})
+	}
+}
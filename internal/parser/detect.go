@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	AutoFormatName     = "auto"
+	defaultDetectLines = 10
+)
+
+const (
+	CommonFormat = `$remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent`
+
+	VhostCombinedFormat = `$server_name:$server_port $remote_addr $remote_logname $remote_user [$time_local] ` +
+		`"$request" $status $body_bytes_sent "$http_referer" "$http_user_agent"`
+
+	ApacheFormat = `$remote_addr $remote_logname $remote_user [$time_local] ` +
+		`"$request" $status $body_bytes_sent "$http_referer" "$http_user_agent"`
+)
+
+type namedFormat struct {
+	name   string
+	format decoder
+}
+
+var (
+	combinedFormat      = MustCompileFormat(CombinedFormat)
+	commonFormat        = MustCompileFormat(CommonFormat)
+	vhostCombinedFormat = MustCompileFormat(VhostCombinedFormat)
+	apacheFormat        = MustCompileFormat(ApacheFormat)
+)
+
+func knownFormats(jsonKeys map[string]string) []namedFormat {
+	return []namedFormat{
+		{name: combinedFormatName, format: combinedFormat},
+		{name: "common", format: commonFormat},
+		{name: "vhost_combined", format: vhostCombinedFormat},
+		{name: JSONFormatName, format: NewJSONFormat(jsonKeys)},
+		{name: "apache", format: apacheFormat},
+	}
+}
+
+func sampleLines(reader io.Reader, limit int) ([]string, io.Reader, error) {
+	buf := bufio.NewReader(reader)
+	sample := &bytes.Buffer{}
+	lines := make([]string, 0, limit)
+
+	for len(lines) < limit {
+		text, err := buf.ReadString('\n')
+		sample.WriteString(text)
+
+		if text = strings.TrimRight(text, "\r\n"); text != "" {
+			lines = append(lines, text)
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("read sample: %w", err)
+		}
+	}
+
+	return lines, io.MultiReader(sample, buf), nil
+}
+
+func scoreFormat(format decoder, lines []string) int {
+	score := 0
+
+	for _, text := range lines {
+		if _, err := format.decode(text); err == nil {
+			score++
+		}
+	}
+
+	return score
+}
+
+func detectFormat(lines []string, candidates []namedFormat) (namedFormat, bool) {
+	best := -1
+	bestScore := 0
+
+	for i, candidate := range candidates {
+		if score := scoreFormat(candidate.format, lines); score > bestScore {
+			best = i
+			bestScore = score
+		}
+	}
+
+	if best == -1 {
+		return namedFormat{}, false
+	}
+
+	return candidates[best], true
+}
+
+func (p *Parser) detectFormats(sources []source, prm *Params) {
+	limit := prm.DetectLines
+	if limit <= 0 {
+		limit = defaultDetectLines
+	}
+
+	candidates := knownFormats(prm.JSONKeys)
+
+	for i := range sources {
+		src := &sources[i]
+		if src.format != nil {
+			continue
+		}
+
+		lines, reader, err := sampleLines(src.reader, limit)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("detect format of %q: %s", src.name, err))
+
+			src.format = p.format
+			src.formatName = combinedFormatName
+
+			continue
+		}
+
+		src.reader = reader
+
+		detected, ok := detectFormat(lines, candidates)
+		if !ok {
+			slog.Debug(fmt.Sprintf("no known format matches %q, using %s", src.name, combinedFormatName))
+
+			detected = namedFormat{name: combinedFormatName, format: p.format}
+		}
+
+		src.format = detected.format
+		src.formatName = detected.name
+	}
+}
@@ -55,3 +55,233 @@ func NewErrNoFiles(msg string) error {
 func (e ErrNoFiles) Error() string {
 	return e.msg
 }
+
+type ErrLogFormat struct {
+	msg string
+}
+
+func NewErrLogFormat(msg string) error {
+	return ErrLogFormat{
+		msg: msg,
+	}
+}
+
+func (e ErrLogFormat) Error() string {
+	return e.msg
+}
+
+type ErrNginxConf struct {
+	msg string
+}
+
+func NewErrNginxConf(msg string) error {
+	return ErrNginxConf{
+		msg: msg,
+	}
+}
+
+func (e ErrNginxConf) Error() string {
+	return e.msg
+}
+
+type ErrJSON struct {
+	msg string
+}
+
+func NewErrJSON(msg string) error {
+	return ErrJSON{
+		msg: msg,
+	}
+}
+
+func (e ErrJSON) Error() string {
+	return e.msg
+}
+
+type ErrLineTooLong struct {
+	msg string
+}
+
+func NewErrLineTooLong(msg string) error {
+	return ErrLineTooLong{
+		msg: msg,
+	}
+}
+
+func (e ErrLineTooLong) Error() string {
+	return e.msg
+}
+
+type ErrMissingTime struct {
+	msg string
+}
+
+func NewErrMissingTime(msg string) error {
+	return ErrMissingTime{
+		msg: msg,
+	}
+}
+
+func (e ErrMissingTime) Error() string {
+	return e.msg
+}
+
+type ErrPolicy struct {
+	msg string
+}
+
+func NewErrPolicy(msg string) error {
+	return ErrPolicy{
+		msg: msg,
+	}
+}
+
+func (e ErrPolicy) Error() string {
+	return e.msg
+}
+
+type ErrTooManyMalformed struct {
+	msg string
+}
+
+func NewErrTooManyMalformed(msg string) error {
+	return ErrTooManyMalformed{
+		msg: msg,
+	}
+}
+
+func (e ErrTooManyMalformed) Error() string {
+	return e.msg
+}
+
+type ErrState struct {
+	msg string
+}
+
+func NewErrState(msg string) error {
+	return ErrState{
+		msg: msg,
+	}
+}
+
+func (e ErrState) Error() string {
+	return e.msg
+}
+
+type ErrFollow struct {
+	msg string
+}
+
+func NewErrFollow(msg string) error {
+	return ErrFollow{
+		msg: msg,
+	}
+}
+
+func (e ErrFollow) Error() string {
+	return e.msg
+}
+
+type ErrHTTPStatus struct {
+	Code int
+	msg  string
+}
+
+func NewErrHTTPStatus(code int, msg string) error {
+	return ErrHTTPStatus{
+		Code: code,
+		msg:  msg,
+	}
+}
+
+func (e ErrHTTPStatus) Error() string {
+	return e.msg
+}
+
+type ErrHTTPTimeout struct {
+	msg string
+}
+
+func NewErrHTTPTimeout(msg string) error {
+	return ErrHTTPTimeout{
+		msg: msg,
+	}
+}
+
+func (e ErrHTTPTimeout) Error() string {
+	return e.msg
+}
+
+type ErrTop struct {
+	msg string
+}
+
+func NewErrTop(msg string) error {
+	return ErrTop{
+		msg: msg,
+	}
+}
+
+func (e ErrTop) Error() string {
+	return e.msg
+}
+
+type ErrFilter struct {
+	Expr   string
+	Column int
+	msg    string
+}
+
+func NewErrFilter(expr string, column int, msg string) error {
+	return ErrFilter{
+		Expr:   expr,
+		Column: column,
+		msg:    msg,
+	}
+}
+
+func (e ErrFilter) Error() string {
+	return e.msg
+}
+
+type ErrUnknownField struct {
+	msg string
+}
+
+func NewErrUnknownField(msg string) error {
+	return ErrUnknownField{
+		msg: msg,
+	}
+}
+
+func (e ErrUnknownField) Error() string {
+	return e.msg
+}
+
+type ErrTimeRange struct {
+	msg string
+}
+
+func NewErrTimeRange(msg string) error {
+	return ErrTimeRange{
+		msg: msg,
+	}
+}
+
+func (e ErrTimeRange) Error() string {
+	return e.msg
+}
+
+type ErrTimeSeries struct {
+	msg string
+}
+
+func NewErrTimeSeries(msg string) error {
+	return ErrTimeSeries{
+		msg: msg,
+	}
+}
+
+func (e ErrTimeSeries) Error() string {
+	return e.msg
+}
@@ -0,0 +1,238 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type fieldKind int
+
+const (
+	fieldString fieldKind = iota
+	fieldNumber
+	fieldTime
+	fieldAddress
+	fieldExtra
+)
+
+var fieldKindNames = map[fieldKind]string{
+	fieldString:  "string",
+	fieldNumber:  "number",
+	fieldTime:    "time",
+	fieldAddress: "address",
+	fieldExtra:   "variable",
+}
+
+type logField struct {
+	name      string
+	variables []string
+	kind      fieldKind
+	str       func(logEntry *log) string
+	num       func(logEntry *log) int
+	time      func(logEntry *log) time.Time
+}
+
+var logFields = []logField{
+	{
+		name:      "RemoteAddress",
+		variables: []string{"remote_addr"},
+		kind:      fieldAddress,
+		str:       func(logEntry *log) string { return logEntry.RemoteAddress },
+	},
+	{
+		name:      "RemoteUser",
+		variables: []string{"remote_user"},
+		kind:      fieldString,
+		str:       func(logEntry *log) string { return logEntry.RemoteUser },
+	},
+	{
+		name:      "TimeLocal",
+		variables: []string{"time_local", "time_iso8601"},
+		kind:      fieldTime,
+		time:      func(logEntry *log) time.Time { return logEntry.TimeLocal },
+	},
+	{
+		name:      "Method",
+		variables: []string{"request_method"},
+		kind:      fieldString,
+		str:       func(logEntry *log) string { return logEntry.Method },
+	},
+	{
+		name:      "URL",
+		variables: []string{"request_uri"},
+		kind:      fieldString,
+		str:       func(logEntry *log) string { return logEntry.URL },
+	},
+	{
+		name:      "HTTPVersion",
+		variables: []string{"server_protocol"},
+		kind:      fieldString,
+		str:       func(logEntry *log) string { return logEntry.HTTPVersion },
+	},
+	{
+		name:      "Status",
+		variables: []string{"status"},
+		kind:      fieldNumber,
+		num:       func(logEntry *log) int { return logEntry.Status },
+	},
+	{
+		name:      "BodyBytesSend",
+		variables: []string{"body_bytes_sent"},
+		kind:      fieldNumber,
+		num:       func(logEntry *log) int { return logEntry.BodyBytesSend },
+	},
+	{
+		name:      "Referer",
+		variables: []string{"http_referer"},
+		kind:      fieldString,
+		str:       func(logEntry *log) string { return logEntry.Referer },
+	},
+	{
+		name:      "UserAgent",
+		variables: []string{"http_user_agent"},
+		kind:      fieldString,
+		str:       func(logEntry *log) string { return logEntry.UserAgent },
+	},
+}
+
+func FieldNames() []string {
+	names := make([]string, len(logFields))
+	for i := range logFields {
+		names[i] = logFields[i].name
+	}
+
+	return names
+}
+
+func lookupField(name string) (*logField, bool) {
+	variable, isVariable := strings.CutPrefix(name, "$")
+
+	for i := range logFields {
+		if !isVariable && strings.EqualFold(logFields[i].name, name) || slices.Contains(logFields[i].variables, variable) {
+			return &logFields[i], true
+		}
+	}
+
+	return nil, false
+}
+
+type fieldRef struct {
+	name  string
+	kind  fieldKind
+	field *logField
+}
+
+func newFieldRef(field *logField) *fieldRef {
+	return &fieldRef{name: field.name, kind: field.kind, field: field}
+}
+
+func newVariableRef(name string) *fieldRef {
+	return &fieldRef{name: name, kind: fieldExtra}
+}
+
+func (f *fieldRef) textAccessor(layout string) func(logEntry *log) (string, bool) {
+	switch {
+	case f.kind == fieldExtra:
+		name := f.name
+
+		return func(logEntry *log) (string, bool) {
+			value, ok := logEntry.Fields[name]
+			return value, ok
+		}
+
+	case f.kind == fieldNumber:
+		get := f.field.num
+
+		return func(logEntry *log) (string, bool) {
+			return strconv.Itoa(get(logEntry)), true
+		}
+
+	case f.kind == fieldTime:
+		get := f.field.time
+
+		return func(logEntry *log) (string, bool) {
+			return get(logEntry).Format(layout), true
+		}
+
+	default:
+		get := f.field.str
+
+		return func(logEntry *log) (string, bool) {
+			return get(logEntry), true
+		}
+	}
+}
+
+func decoderVariables(decoders ...decoder) ([]string, bool) {
+	variables := make([]string, 0)
+	open := false
+
+	for _, dec := range decoders {
+		if _, ok := dec.(*JSONFormat); ok {
+			open = true
+		}
+
+		format, ok := dec.(*Format)
+		if !ok {
+			continue
+		}
+
+		for _, variable := range format.Variables() {
+			_, known := lookupField("$" + variable)
+			if !known && variable != "request" && !slices.Contains(variables, variable) {
+				variables = append(variables, variable)
+			}
+		}
+	}
+
+	return variables, open
+}
+
+func sourceDecoders(sources []source) []decoder {
+	decoders := make([]decoder, len(sources))
+	for i := range sources {
+		decoders[i] = sources[i].format
+	}
+
+	return decoders
+}
+
+func resolveFilterField(field string, variables []string, open bool) (*fieldRef, error) {
+	if known, ok := lookupField(field); ok {
+		return newFieldRef(known), nil
+	}
+
+	variable := strings.TrimPrefix(field, "$")
+	if open || slices.Contains(variables, variable) {
+		return newVariableRef(variable), nil
+	}
+
+	valid := append(FieldNames(), variables...)
+
+	return nil, NewErrUnknownField(fmt.Sprintf("unknown field %q, valid fields: %s", field, strings.Join(valid, ", ")))
+}
+
+func CompileFieldFilter(field, pattern string, variables []string, open bool) (*Filter, error) {
+	if field == "" {
+		return nil, nil
+	}
+
+	ref, err := resolveFilterField(field, variables, open)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compile filter value %q: %w", pattern, err)
+	}
+
+	return &Filter{
+		expr: fmt.Sprintf("%s ~ %q", field, pattern),
+		root: newRegexpNode(ref, re, false, timeLayout),
+	}, nil
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package parser
+
+import "os"
+
+func fileID(_ os.FileInfo) uint64 {
+	return 0
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package parser
+
+import (
+	"os"
+	"syscall"
+)
+
+func fileID(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(stat.Ino) //nolint:unconvert // Ino is not uint64 on every platform
+	}
+
+	return 0
+}
@@ -0,0 +1,723 @@
+package parser
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenEq
+	tokenNe
+	tokenLt
+	tokenLe
+	tokenGt
+	tokenGe
+	tokenMatch
+	tokenNotMatch
+	tokenIn
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+var tokenNames = map[tokenKind]string{
+	tokenEOF:      "end of expression",
+	tokenEq:       `"=="`,
+	tokenNe:       `"!="`,
+	tokenLt:       `"<"`,
+	tokenLe:       `"<="`,
+	tokenGt:       `">"`,
+	tokenGe:       `">="`,
+	tokenMatch:    `"~"`,
+	tokenNotMatch: `"!~"`,
+	tokenIn:       `"in"`,
+	tokenAnd:      `"&&"`,
+	tokenOr:       `"||"`,
+	tokenNot:      `"!"`,
+	tokenLParen:   `"("`,
+	tokenRParen:   `")"`,
+	tokenComma:    `","`,
+}
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value string
+	pos   int
+}
+
+func (t *token) String() string {
+	switch t.kind {
+	case tokenIdent, tokenString, tokenNumber:
+		return strconv.Quote(t.text)
+
+	default:
+		return tokenNames[t.kind]
+	}
+}
+
+var operators = []struct {
+	text string
+	kind tokenKind
+}{
+	{text: "==", kind: tokenEq},
+	{text: "!=", kind: tokenNe},
+	{text: "!~", kind: tokenNotMatch},
+	{text: "<=", kind: tokenLe},
+	{text: ">=", kind: tokenGe},
+	{text: "&&", kind: tokenAnd},
+	{text: "||", kind: tokenOr},
+	{text: "<", kind: tokenLt},
+	{text: ">", kind: tokenGt},
+	{text: "~", kind: tokenMatch},
+	{text: "!", kind: tokenNot},
+	{text: "(", kind: tokenLParen},
+	{text: ")", kind: tokenRParen},
+	{text: ",", kind: tokenComma},
+}
+
+func filterError(expr string, pos int, msg string) error {
+	column := utf8.RuneCountInString(expr[:pos]) + 1
+	return NewErrFilter(expr, column, fmt.Sprintf("%s at column %d", msg, column))
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '$' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func scanString(expr string, start int) (token, error) {
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			i++
+
+		case '"':
+			value, err := strconv.Unquote(expr[start : i+1])
+			if err != nil {
+				return token{}, filterError(expr, start, "invalid string literal")
+			}
+
+			return token{kind: tokenString, text: expr[start : i+1], value: value, pos: start}, nil
+		}
+	}
+
+	return token{}, filterError(expr, start, "unterminated string")
+}
+
+func scanNumber(expr string, start int) token {
+	end := start
+	if expr[end] == '-' {
+		end++
+	}
+
+	for end < len(expr) && (expr[end] >= '0' && expr[end] <= '9' || expr[end] == '.') {
+		end++
+	}
+
+	return token{kind: tokenNumber, text: expr[start:end], value: expr[start:end], pos: start}
+}
+
+func scanToken(expr string, pos int) (token, error) {
+	r, size := utf8.DecodeRuneInString(expr[pos:])
+
+	switch {
+	case r == '"':
+		return scanString(expr, pos)
+
+	case r >= '0' && r <= '9' || r == '-' && pos+1 < len(expr) && expr[pos+1] >= '0' && expr[pos+1] <= '9':
+		return scanNumber(expr, pos), nil
+
+	case isIdentRune(r):
+		end := pos + size
+		for end < len(expr) {
+			r, size := utf8.DecodeRuneInString(expr[end:])
+			if !isIdentRune(r) {
+				break
+			}
+
+			end += size
+		}
+
+		if expr[pos:end] == "in" {
+			return token{kind: tokenIn, text: "in", pos: pos}, nil
+		}
+
+		return token{kind: tokenIdent, text: expr[pos:end], value: expr[pos:end], pos: pos}, nil
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(expr[pos:], op.text) {
+			return token{kind: op.kind, text: op.text, pos: pos}, nil
+		}
+	}
+
+	return token{}, filterError(expr, pos, fmt.Sprintf("unexpected character %q", r))
+}
+
+func scanFilter(expr string) ([]token, error) {
+	tokens := make([]token, 0)
+
+	for pos := 0; pos < len(expr); {
+		r, size := utf8.DecodeRuneInString(expr[pos:])
+		if unicode.IsSpace(r) {
+			pos += size
+			continue
+		}
+
+		tok, err := scanToken(expr, pos)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, tok)
+		pos += len(tok.text)
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(expr)}), nil
+}
+
+var filterTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	time.DateTime,
+	time.DateOnly,
+	timeLocalLayout,
+}
+
+func parseFilterTime(value string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range filterTimeLayouts {
+		if tm, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return tm, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+type filterNode interface {
+	eval(logEntry *log) bool
+}
+
+type orNode struct {
+	left, right filterNode
+}
+
+func (n *orNode) eval(logEntry *log) bool {
+	return n.left.eval(logEntry) || n.right.eval(logEntry)
+}
+
+type andNode struct {
+	left, right filterNode
+}
+
+func (n *andNode) eval(logEntry *log) bool {
+	return n.left.eval(logEntry) && n.right.eval(logEntry)
+}
+
+type notNode struct {
+	expr filterNode
+}
+
+func (n *notNode) eval(logEntry *log) bool {
+	return !n.expr.eval(logEntry)
+}
+
+type compareNode struct {
+	match func(logEntry *log) bool
+}
+
+func (n *compareNode) eval(logEntry *log) bool {
+	return n.match(logEntry)
+}
+
+func orderedPredicate[T int | float64 | string](op tokenKind) func(a, b T) bool {
+	switch op {
+	case tokenNe:
+		return func(a, b T) bool { return a != b }
+
+	case tokenLt:
+		return func(a, b T) bool { return a < b }
+
+	case tokenLe:
+		return func(a, b T) bool { return a <= b }
+
+	case tokenGt:
+		return func(a, b T) bool { return a > b }
+
+	case tokenGe:
+		return func(a, b T) bool { return a >= b }
+
+	default:
+		return func(a, b T) bool { return a == b }
+	}
+}
+
+func matchAny[T any](value T, values []T, compare func(a, b T) bool) bool {
+	for _, other := range values {
+		if compare(value, other) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type comparisonValues struct {
+	strs     []string
+	numbers  []float64
+	times    []time.Time
+	prefixes []netip.Prefix
+}
+
+func newRegexpNode(field *fieldRef, re *regexp.Regexp, negate bool, layout string) filterNode {
+	text := field.textAccessor(layout)
+
+	return &compareNode{match: func(logEntry *log) bool {
+		value, ok := text(logEntry)
+		return ok && re.MatchString(value) != negate
+	}}
+}
+
+func newAddressNode(field *fieldRef, op tokenKind, prefixes []netip.Prefix) filterNode {
+	get := field.field.str
+	negate := op == tokenNe
+
+	return &compareNode{match: func(logEntry *log) bool {
+		addr, err := netip.ParseAddr(get(logEntry))
+		if err != nil {
+			return negate
+		}
+
+		addr = addr.Unmap()
+		for _, prefix := range prefixes {
+			if prefix.Contains(addr) {
+				return !negate
+			}
+		}
+
+		return negate
+	}}
+}
+
+func newVariableNode(field *fieldRef, op tokenKind, values *comparisonValues) filterNode {
+	name := field.name
+
+	if values.numbers == nil {
+		compare := orderedPredicate[string](op)
+
+		return &compareNode{match: func(logEntry *log) bool {
+			value, ok := logEntry.Fields[name]
+			return ok && matchAny(value, values.strs, compare)
+		}}
+	}
+
+	compare := orderedPredicate[float64](op)
+
+	return &compareNode{match: func(logEntry *log) bool {
+		value, ok := logEntry.Fields[name]
+		if !ok {
+			return false
+		}
+
+		number, err := strconv.ParseFloat(value, 64)
+
+		return err == nil && matchAny(number, values.numbers, compare)
+	}}
+}
+
+func newCompareNode(field *fieldRef, op tokenKind, values *comparisonValues) filterNode {
+	switch field.kind {
+	case fieldAddress:
+		return newAddressNode(field, op, values.prefixes)
+
+	case fieldExtra:
+		return newVariableNode(field, op, values)
+
+	case fieldNumber:
+		get := field.field.num
+		compare := orderedPredicate[float64](op)
+
+		return &compareNode{match: func(logEntry *log) bool {
+			return matchAny(float64(get(logEntry)), values.numbers, compare)
+		}}
+
+	case fieldTime:
+		get := field.field.time
+		compareInt := orderedPredicate[int](op)
+		compare := func(a, b time.Time) bool { return compareInt(a.Compare(b), 0) }
+
+		return &compareNode{match: func(logEntry *log) bool {
+			return matchAny(get(logEntry), values.times, compare)
+		}}
+
+	default:
+		get := field.field.str
+		compare := orderedPredicate[string](op)
+
+		return &compareNode{match: func(logEntry *log) bool {
+			return matchAny(get(logEntry), values.strs, compare)
+		}}
+	}
+}
+
+type filterParser struct {
+	expr      string
+	tokens    []token
+	pos       int
+	loc       *time.Location
+	variables []string
+	open      bool
+}
+
+func (p *filterParser) peek() *token {
+	return &p.tokens[p.pos]
+}
+
+func (p *filterParser) next() *token {
+	tok := &p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *filterParser) errorAt(tok *token, msg string) error {
+	return filterError(p.expr, tok.pos, msg)
+}
+
+func (p *filterParser) expect(kind tokenKind) (*token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return nil, p.errorAt(tok, fmt.Sprintf("expected %s, found %s", tokenNames[kind], tok))
+	}
+
+	return tok, nil
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &orNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &andNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	switch p.peek().kind {
+	case tokenNot:
+		p.next()
+
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &notNode{expr: expr}, nil
+
+	case tokenLParen:
+		p.next()
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.expect(tokenRParen); err != nil {
+			return nil, err
+		}
+
+		return expr, nil
+
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *filterParser) parseValues() ([]*token, error) {
+	if _, err := p.expect(tokenLParen); err != nil {
+		return nil, err
+	}
+
+	values := make([]*token, 0)
+
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+
+		tok := p.next()
+		if tok.kind == tokenRParen {
+			return values, nil
+		}
+
+		if tok.kind != tokenComma {
+			return nil, p.errorAt(tok, fmt.Sprintf(`expected "," or ")", found %s`, tok))
+		}
+	}
+}
+
+func (p *filterParser) parseValue() (*token, error) {
+	tok := p.next()
+	if tok.kind != tokenString && tok.kind != tokenNumber {
+		return nil, p.errorAt(tok, fmt.Sprintf("expected string or number, found %s", tok))
+	}
+
+	return tok, nil
+}
+
+func (p *filterParser) resolveField(tok *token) (*fieldRef, error) {
+	field, err := resolveFilterField(tok.value, p.variables, p.open)
+	if err != nil {
+		return nil, p.errorAt(tok, err.Error())
+	}
+
+	return field, nil
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != tokenIdent {
+		return nil, p.errorAt(fieldTok, fmt.Sprintf("expected field name, found %s", fieldTok))
+	}
+
+	field, err := p.resolveField(fieldTok)
+	if err != nil {
+		return nil, err
+	}
+
+	opTok := p.next()
+
+	var values []*token
+
+	switch opTok.kind {
+	case tokenIn:
+		values, err = p.parseValues()
+
+	case tokenEq, tokenNe, tokenLt, tokenLe, tokenGt, tokenGe, tokenMatch, tokenNotMatch:
+		var value *token
+
+		value, err = p.parseValue()
+		values = []*token{value}
+
+	default:
+		return nil, p.errorAt(opTok, fmt.Sprintf("expected comparison operator, found %s", opTok))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p.compileComparison(field, opTok, values)
+}
+
+func (p *filterParser) compileRegexp(value *token) (*regexp.Regexp, error) {
+	if value.kind != tokenString {
+		return nil, p.errorAt(value, "regular expression must be a string")
+	}
+
+	re, err := regexp.Compile(value.value)
+	if err != nil {
+		return nil, p.errorAt(value, fmt.Sprintf("invalid regular expression: %s", err))
+	}
+
+	return re, nil
+}
+
+func (p *filterParser) compileValue(field *fieldRef, values *comparisonValues, opTok, value *token) error {
+	kind := field.kind
+
+	switch {
+	case kind == fieldNumber || kind == fieldExtra && value.kind == tokenNumber:
+		number, err := strconv.ParseFloat(value.value, 64)
+		if err != nil {
+			return p.errorAt(value, fmt.Sprintf("%s field %s must be compared with a number", fieldKindNames[kind], field.name))
+		}
+
+		values.numbers = append(values.numbers, number)
+
+	case value.kind != tokenString:
+		return p.errorAt(value, fmt.Sprintf("%s field %s must be compared with a string", fieldKindNames[kind], field.name))
+
+	case kind == fieldTime:
+		tm, ok := parseFilterTime(value.value, p.loc)
+		if !ok {
+			return p.errorAt(value, fmt.Sprintf("invalid time %q", value.value))
+		}
+
+		values.times = append(values.times, tm)
+
+	case kind == fieldAddress:
+		if opTok.kind != tokenEq && opTok.kind != tokenNe && opTok.kind != tokenIn {
+			return p.errorAt(opTok, fmt.Sprintf("operator %s is not supported for addresses", opTok))
+		}
+
+		prefix, err := netip.ParsePrefix(value.value)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(value.value)
+			if addrErr != nil {
+				return p.errorAt(value, fmt.Sprintf("invalid address or CIDR %q", value.value))
+			}
+
+			prefix = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+		}
+
+		values.prefixes = append(values.prefixes, prefix.Masked())
+
+	default:
+		values.strs = append(values.strs, value.value)
+	}
+
+	return nil
+}
+
+func (p *filterParser) compileComparison(field *fieldRef, opTok *token, values []*token) (filterNode, error) {
+	if opTok.kind == tokenMatch || opTok.kind == tokenNotMatch {
+		re, err := p.compileRegexp(values[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return newRegexpNode(field, re, opTok.kind == tokenNotMatch, timeLocalLayout), nil
+	}
+
+	if field.kind == fieldExtra {
+		kind := values[0].kind
+		for _, value := range values[1:] {
+			if value.kind != kind {
+				return nil, p.errorAt(value, "values of a list must have the same type")
+			}
+		}
+	}
+
+	compiled := &comparisonValues{}
+
+	for _, value := range values {
+		if err := p.compileValue(field, compiled, opTok, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return newCompareNode(field, opTok.kind, compiled), nil
+}
+
+type Filter struct {
+	expr string
+	root filterNode
+}
+
+func CompileFilter(expr string, variables []string, open bool) (*Filter, error) {
+	return compileFilterIn(expr, nil, variables, open)
+}
+
+func compileFilterIn(expr string, loc *time.Location, variables []string, open bool) (*Filter, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	tokens, err := scanFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{
+		expr:      expr,
+		tokens:    tokens,
+		loc:       loc,
+		variables: variables,
+		open:      open,
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected %s", tok))
+	}
+
+	return &Filter{expr: expr, root: root}, nil
+}
+
+func (f *Filter) String() string {
+	return f.expr
+}
+
+func (f *Filter) and(other *Filter) *Filter {
+	switch {
+	case f == nil:
+		return other
+
+	case other == nil:
+		return f
+
+	default:
+		return &Filter{
+			expr: fmt.Sprintf("(%s) && (%s)", f.expr, other.expr),
+			root: &andNode{left: f.root, right: other.root},
+		}
+	}
+}
+
+func (f *Filter) match(logEntry *log) bool {
+	if f == nil {
+		return true
+	}
+
+	return f.root.eval(logEntry)
+}
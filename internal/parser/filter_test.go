@@ -0,0 +1,402 @@
+package parser_test
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const filterLogs = `10.0.0.1 - - [22/Oct/2024:09:00:00 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0" 0.010
+10.0.0.2 - - [22/Oct/2024:09:15:00 +0000] "POST /b HTTP/1.1" 500 200 "-" "Mozilla/5.0" 1.500
+10.0.1.3 - - [22/Oct/2024:09:30:00 +0000] "PUT /c HTTP/1.1" 503 300 "-" "Googlebot/2.1" 0.200
+192.168.0.4 - - [22/Oct/2024:10:00:00 +0000] "DELETE /d HTTP/1.1" 404 400 "-" "curl/8.0" 2.000
+2001:db8::5 - - [23/Oct/2024:09:00:00 +0000] "GET /e?q=1 HTTP/1.1" 499 0 "-" "bingbot/2.0" 0.001
+`
+
+const filterLogFormat = `$remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent ` +
+	`"$http_referer" "$http_user_agent" $request_time`
+
+func TestParseWithFilter(t *testing.T) {
+	tt := []struct {
+		name   string
+		filter string
+		total  int
+	}{
+		{
+			name:   "example from the documentation",
+			filter: `Status >= 500 && Method in ("POST","PUT") && !(UserAgent ~ "bot")`,
+			total:  1,
+		},
+		{
+			name:   "or and parentheses",
+			filter: `(Status == 200 || Status == 404) && UserAgent == "curl/8.0"`,
+			total:  2,
+		},
+		{
+			name:   "not equal",
+			filter: `Method != "GET"`,
+			total:  3,
+		},
+		{
+			name:   "string ordering",
+			filter: `URL >= "/c"`,
+			total:  3,
+		},
+		{
+			name:   "regexp",
+			filter: `URL ~ "^/[ab]$"`,
+			total:  2,
+		},
+		{
+			name:   "not regexp",
+			filter: `UserAgent !~ "(?i)bot"`,
+			total:  3,
+		},
+		{
+			name:   "number regexp",
+			filter: `Status ~ "^5"`,
+			total:  2,
+		},
+		{
+			name:   "cidr",
+			filter: `RemoteAddress == "10.0.0.0/24"`,
+			total:  2,
+		},
+		{
+			name:   "cidr list and address",
+			filter: `RemoteAddress in ("10.0.0.0/8", "2001:db8::5")`,
+			total:  4,
+		},
+		{
+			name:   "not in cidr",
+			filter: `RemoteAddress != "10.0.0.0/8"`,
+			total:  2,
+		},
+		{
+			name:   "time range",
+			filter: `TimeLocal >= "2024-10-22T09:15:00Z" && TimeLocal < "2024-10-22T10:00:00Z"`,
+			total:  2,
+		},
+		{
+			name:   "time with zone and date",
+			filter: `TimeLocal > "2024-10-22T11:00:00+02:00" && TimeLocal < "2024-10-23"`,
+			total:  3,
+		},
+		{
+			name:   "nginx variable of a known field",
+			filter: `$status in (404, 499) && $request_method == "GET"`,
+			total:  1,
+		},
+		{
+			name:   "numeric variable",
+			filter: `$request_time > 1`,
+			total:  2,
+		},
+		{
+			name:   "string variable",
+			filter: `$request_time == "0.200"`,
+			total:  1,
+		},
+		{
+			name:   "variable without dollar",
+			filter: `request_time == "0.200"`,
+			total:  1,
+		},
+		{
+			name:   "case-insensitive field and double negation",
+			filter: `!!(url == "/a")`,
+			total:  1,
+		},
+		{
+			name:   "and binds tighter than or",
+			filter: `Status == 200 || Status == 404 && Method == "GET"`,
+			total:  1,
+		},
+		{
+			name:   "negative number",
+			filter: `BodyBytesSend > -1`,
+			total:  5,
+		},
+	}
+
+	fileName := createTestFiles(t, filterLogs)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			logParser := parser.New()
+
+			data, err := logParser.Parse(parser.Params{
+				Paths:     []string{fileName},
+				LogFormat: filterLogFormat,
+				Filter:    tc.filter,
+			})
+			require.NoError(t, err, "file must be parsed")
+
+			assert.Equal(t, tc.total, data.TotalRequests)
+		})
+	}
+}
+
+func TestCompileFilterError(t *testing.T) {
+	tt := []struct {
+		name   string
+		filter string
+		column int
+		msg    string
+	}{
+		{
+			name:   "unknown field",
+			filter: `Status >= 500 && Agent ~ "bot"`,
+			column: 18,
+			msg: `unknown field "Agent", valid fields: RemoteAddress, RemoteUser, TimeLocal, Method, URL, HTTPVersion, ` +
+				`Status, BodyBytesSend, Referer, UserAgent, upstream_status at column 18`,
+		},
+		{
+			name:   "variable missing from the format",
+			filter: `$upstrem_status ~ "50."`,
+			column: 1,
+			msg: `unknown field "$upstrem_status", valid fields: RemoteAddress, RemoteUser, TimeLocal, Method, URL, ` +
+				`HTTPVersion, Status, BodyBytesSend, Referer, UserAgent, upstream_status at column 1`,
+		},
+		{
+			name:   "missing value",
+			filter: `Status >= `,
+			column: 11,
+			msg:    "expected string or number, found end of expression at column 11",
+		},
+		{
+			name:   "missing parenthesis",
+			filter: `(Status == 200 || Status == 404`,
+			column: 32,
+			msg:    `expected ")", found end of expression at column 32`,
+		},
+		{
+			name:   "trailing tokens",
+			filter: `Status == 200 )`,
+			column: 15,
+			msg:    `unexpected ")" at column 15`,
+		},
+		{
+			name:   "missing operator",
+			filter: `Status 200`,
+			column: 8,
+			msg:    `expected comparison operator, found "200" at column 8`,
+		},
+		{
+			name:   "number field with string",
+			filter: `Status == "OK"`,
+			column: 11,
+			msg:    "number field Status must be compared with a number at column 11",
+		},
+		{
+			name:   "string field with number",
+			filter: `Method in ("GET", 5)`,
+			column: 19,
+			msg:    "string field Method must be compared with a string at column 19",
+		},
+		{
+			name:   "invalid time",
+			filter: `TimeLocal > "yesterday"`,
+			column: 13,
+			msg:    `invalid time "yesterday" at column 13`,
+		},
+		{
+			name:   "invalid cidr",
+			filter: `RemoteAddress == "10.0.0.0/33"`,
+			column: 18,
+			msg:    `invalid address or CIDR "10.0.0.0/33" at column 18`,
+		},
+		{
+			name:   "address ordering",
+			filter: `RemoteAddress < "10.0.0.1"`,
+			column: 15,
+			msg:    `operator "<" is not supported for addresses at column 15`,
+		},
+		{
+			name:   "invalid regexp",
+			filter: `URL ~ "("`,
+			column: 7,
+			msg:    "invalid regular expression: error parsing regexp: missing closing ): `(` at column 7",
+		},
+		{
+			name:   "unterminated string",
+			filter: `Method == "GET`,
+			column: 11,
+			msg:    "unterminated string at column 11",
+		},
+		{
+			name:   "unexpected character",
+			filter: `Method = "GET"`,
+			column: 8,
+			msg:    `unexpected character '=' at column 8`,
+		},
+		{
+			name:   "mixed variable list",
+			filter: `$upstream_status in (502, "504")`,
+			column: 27,
+			msg:    "values of a list must have the same type at column 27",
+		},
+		{
+			name:   "column counts runes",
+			filter: `UserAgent == "бот" &&`,
+			column: 22,
+			msg:    "expected field name, found end of expression at column 22",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parser.CompileFilter(tc.filter, []string{"upstream_status"}, false)
+
+			var filterErr parser.ErrFilter
+
+			require.ErrorAs(t, err, &filterErr)
+			assert.Equal(t, tc.filter, filterErr.Expr)
+			assert.Equal(t, tc.column, filterErr.Column)
+			assert.EqualError(t, err, tc.msg)
+		})
+	}
+}
+
+func TestCompileEmptyFilter(t *testing.T) {
+	filter, err := parser.CompileFilter("  ", nil, false)
+	require.NoError(t, err, "empty filter must be compiled")
+	assert.Nil(t, filter)
+}
+
+func TestParseFieldFilterError(t *testing.T) {
+	tt := []struct {
+		name      string
+		logFormat string
+		field     string
+		value     string
+		msg       string
+	}{
+		{
+			name:  "misspelled field",
+			field: "Stauts",
+			value: "500",
+			msg: `compile field filter: unknown field "Stauts", valid fields: RemoteAddress, RemoteUser, TimeLocal, ` +
+				`Method, URL, HTTPVersion, Status, BodyBytesSend, Referer, UserAgent`,
+		},
+		{
+			name:      "variable missing from the format",
+			logFormat: filterLogFormat,
+			field:     "upstream_addr",
+			value:     ":8081$",
+			msg: `compile field filter: unknown field "upstream_addr", valid fields: RemoteAddress, RemoteUser, TimeLocal, ` +
+				`Method, URL, HTTPVersion, Status, BodyBytesSend, Referer, UserAgent, request_time`,
+		},
+		{
+			name:      "misspelled variable",
+			logFormat: filterLogFormat,
+			field:     "$request_tme",
+			value:     "^0",
+			msg: `compile field filter: unknown field "$request_tme", valid fields: RemoteAddress, RemoteUser, TimeLocal, ` +
+				`Method, URL, HTTPVersion, Status, BodyBytesSend, Referer, UserAgent, request_time`,
+		},
+		{
+			name:  "invalid pattern",
+			field: "URL",
+			value: "(",
+			msg:   "compile field filter: compile filter value \"(\": error parsing regexp: missing closing ): `(`",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fileName := createTestFiles(t, filterLogs)
+			defer deleteTestFiles(t, getRoot(fileName))
+
+			_, err := parser.New().Parse(parser.Params{
+				Paths:       []string{fileName},
+				LogFormat:   tc.logFormat,
+				FilterField: tc.field,
+				FilterValue: tc.value,
+			})
+			assert.EqualError(t, err, tc.msg)
+		})
+	}
+}
+
+func TestCompileFieldFilterUnknownField(t *testing.T) {
+	_, err := parser.CompileFieldFilter("Agent", "bot", nil, false)
+	require.ErrorAs(t, err, &parser.ErrUnknownField{})
+
+	_, err = parser.CompileFieldFilter("$request_id", "^abc$", nil, false)
+	require.ErrorAs(t, err, &parser.ErrUnknownField{})
+
+	filter, err := parser.CompileFieldFilter("request_id", "^abc$", nil, true)
+	require.NoError(t, err, "any variable must be accepted for JSON logs")
+	assert.Equal(t, `request_id ~ "^abc$"`, filter.String())
+
+	filter, err = parser.CompileFilter(`$request_id == "abc"`, nil, true)
+	require.NoError(t, err, "any variable must be accepted for JSON logs")
+	assert.Equal(t, `$request_id == "abc"`, filter.String())
+}
+
+const benchmarkLines = 2_000_000
+
+func writeBenchmarkLogs(b *testing.B) string {
+	b.Helper()
+
+	path := filepath.Join(b.TempDir(), "access.log")
+
+	f, err := os.Create(path)
+	require.NoError(b, err, "fixture must be created")
+
+	w := bufio.NewWriter(f)
+
+	for i := range benchmarkLines {
+		fmt.Fprintf(w, `10.0.%d.%d - - [22/Oct/2024:%02d:%02d:%02d +0000] "GET /item/%d HTTP/1.1" %d %d "-" "curl/8.0"`+"\n",
+			i%256, i%251, i/3600%24, i/60%60, i%60, i%1000, []int{200, 301, 404, 500}[i%4], i%5000)
+	}
+
+	require.NoError(b, w.Flush(), "fixture must be written")
+	require.NoError(b, f.Close(), "fixture must be closed")
+
+	return path
+}
+
+func BenchmarkParseWithFilter(b *testing.B) {
+	path := writeBenchmarkLogs(b)
+
+	bb := []struct {
+		name string
+		prm  parser.Params
+	}{
+		{
+			name: "field filter",
+			prm:  parser.Params{FilterField: "URL", FilterValue: "^/none"},
+		},
+		{
+			name: "expression",
+			prm:  parser.Params{Filter: `URL ~ "^/none"`},
+		},
+		{
+			name: "typed expression",
+			prm:  parser.Params{Filter: `Status > 599 && RemoteAddress in ("10.0.0.0/16")`},
+		},
+	}
+
+	for _, bc := range bb {
+		b.Run(bc.name, func(b *testing.B) {
+			prm := bc.prm
+			prm.Paths = []string{path}
+
+			for range b.N {
+				_, err := parser.New().Parse(prm)
+				require.NoError(b, err, "fixture must be parsed")
+			}
+
+			b.ReportMetric(float64(benchmarkLines*b.N)/b.Elapsed().Seconds(), "lines/s")
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultPollInterval   = time.Second
+	defaultRenderInterval = 10 * time.Second
+)
+
+type followPattern struct {
+	pattern    string
+	format     decoder
+	formatName string
+}
+
+type follower struct {
+	parser     *Parser
+	prm        *Params
+	patterns   []followPattern
+	parseData  *data
+	tailers    map[string]*tailer
+	archives   map[string]bool
+	rotated    []tailPosition
+	discovered bool
+}
+
+func (f *follower) close() {
+	for _, t := range f.tailers {
+		closeResource(t)
+	}
+}
+
+func (f *follower) discover() error {
+	for _, pattern := range f.patterns {
+		if err := f.discoverPattern(pattern); err != nil {
+			return err
+		}
+	}
+
+	f.discovered = true
+
+	return nil
+}
+
+func (f *follower) discoverPattern(pattern followPattern) error {
+	paths, err := filepath.Glob(pattern.pattern)
+	if err != nil {
+		return fmt.Errorf("find files for pattern %q: %w", pattern.pattern, err)
+	}
+
+	for _, path := range paths {
+		if _, ok := f.tailers[path]; ok || f.archives[path] {
+			continue
+		}
+
+		t, err := f.openPath(path, pattern.format)
+		if err != nil {
+			return err
+		}
+
+		if t == nil {
+			continue
+		}
+
+		f.tailers[path] = t
+
+		if pattern.format != nil {
+			f.parseData.addSource(path, pattern.formatName)
+		}
+	}
+
+	return nil
+}
+
+func (f *follower) openPath(path string, format decoder) (*tailer, error) {
+	t, err := openTailer(path, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.sniff(); err != nil {
+		closeResource(t)
+		return nil, err
+	}
+
+	if t.compression != compressionNone {
+		return f.openArchive(t)
+	}
+
+	if f.following(t.info) {
+		closeResource(t)
+		return nil, nil
+	}
+
+	if err := f.resume(t); err != nil {
+		closeResource(t)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (f *follower) openArchive(t *tailer) (*tailer, error) {
+	f.archives[t.path] = true
+
+	if f.discovered {
+		closeResource(t)
+		return nil, nil
+	}
+
+	if err := t.decompress(); err != nil {
+		closeResource(t)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (f *follower) following(info os.FileInfo) bool {
+	for _, t := range f.tailers {
+		if os.SameFile(info, t.info) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *follower) resume(t *tailer) error {
+	for i, pos := range f.rotated {
+		if os.SameFile(t.info, pos.info) {
+			f.rotated = slices.Delete(f.rotated, i, i+1)
+			return t.resume(pos)
+		}
+	}
+
+	return nil
+}
+
+func (f *follower) detect(t *tailer, lines []line) {
+	limit := f.prm.DetectLines
+	if limit <= 0 {
+		limit = defaultDetectLines
+	}
+
+	sample := make([]string, 0, limit)
+	for i := 0; i < len(lines) && i < limit; i++ {
+		sample = append(sample, lines[i].text)
+	}
+
+	detected, ok := detectFormat(sample, knownFormats(f.prm.JSONKeys))
+	if !ok {
+		detected = namedFormat{name: combinedFormatName, format: f.parser.format}
+	}
+
+	t.format = detected.format
+	f.parseData.addSource(t.path, detected.name)
+
+	for i := range lines {
+		lines[i].format = t.format
+	}
+}
+
+func (f *follower) poll(ctx context.Context, out chan<- line) error {
+	if err := f.discover(); err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(f.tailers))
+	for path := range f.tailers {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		t := f.tailers[path]
+
+		lines, err := t.poll()
+
+		f.rotated = append(f.rotated, t.rotated...)
+		t.rotated = nil
+
+		if err != nil {
+			return err
+		}
+
+		if t.format == nil && len(lines) != 0 {
+			f.detect(t, lines)
+		}
+
+		for _, curLine := range lines {
+			select {
+			case out <- curLine:
+
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *Parser) followPatterns(prm *Params) ([]followPattern, error) {
+	patterns := make([]followPattern, 0, len(prm.Paths))
+
+	if prm.NginxConf != "" {
+		nginxLogs, err := ReadNginxConf(prm.NginxConf)
+		if err != nil {
+			return nil, fmt.Errorf("ReadNginxConf(%q): %w", prm.NginxConf, err)
+		}
+
+		for _, nginxLog := range nginxLogs {
+			format, err := compileDecoder(nginxLog.Format, nginxLog.Escape, prm.JSONKeys)
+			if err != nil {
+				return nil, fmt.Errorf("compile log format %q: %w", nginxLog.FormatName, err)
+			}
+
+			patterns = append(patterns, followPattern{pattern: nginxLog.Path, format: format, formatName: nginxLog.FormatName})
+		}
+
+		if len(patterns) == 0 {
+			return nil, NewErrNoFiles(fmt.Sprintf("no access logs in %q", prm.NginxConf))
+		}
+	}
+
+	if len(prm.Paths) == 0 {
+		if len(patterns) == 0 {
+			return nil, NewErrNoFiles("no paths to read logs from")
+		}
+
+		return patterns, nil
+	}
+
+	format, formatName, err := p.logFormat(prm)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range prm.Paths {
+		if _, err := parseURL(path); err == nil || path == StdinPath {
+			return nil, NewErrFollow(fmt.Sprintf("only local files can be followed, got %q", path))
+		}
+
+		patterns = append(patterns, followPattern{pattern: path, format: format, formatName: formatName})
+	}
+
+	return patterns, nil
+}
+
+func followDecoders(patterns []followPattern) []decoder {
+	decoders := make([]decoder, len(patterns))
+	for i := range patterns {
+		decoders[i] = patterns[i].format
+	}
+
+	return decoders
+}
+
+func checkFollowParams(prm *Params) error {
+	if prm.StateFile != "" {
+		return NewErrFollow("state file can not be used in follow mode")
+	}
+
+	if prm.ErrorPolicy.MaxPercent > 0 {
+		return NewErrFollow("max error percent can not be used in follow mode")
+	}
+
+	return nil
+}
+
+func (p *Parser) tail(
+	ctx context.Context,
+	eg *errgroup.Group,
+	prm *Params,
+	patterns []followPattern,
+	parseData *data,
+) <-chan line {
+	pollInterval := prm.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+
+	f := &follower{
+		parser:    p,
+		prm:       prm,
+		patterns:  patterns,
+		parseData: parseData,
+		tailers:   make(map[string]*tailer),
+		archives:  make(map[string]bool),
+	}
+
+	lines := make(chan line)
+
+	eg.Go(func() error {
+		defer close(lines)
+		defer f.close()
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := f.poll(ctx, lines); err != nil {
+				return err
+			}
+
+			select {
+			case <-ticker.C:
+
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	})
+
+	return lines
+}
+
+func (p *Parser) Follow(
+	ctx context.Context,
+	prm Params,
+	refresh <-chan struct{},
+	report func(info *domain.FileInfo) error,
+) error {
+	renderInterval := prm.RenderInterval
+	if renderInterval <= 0 {
+		renderInterval = defaultRenderInterval
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if err := checkFollowParams(&prm); err != nil {
+		return err
+	}
+
+	patterns, err := p.followPatterns(&prm)
+	if err != nil {
+		return err
+	}
+
+	if err := checkTimeRange(&prm, followDecoders(patterns)...); err != nil {
+		return err
+	}
+
+	filter, err := compileFilters(&prm, followDecoders(patterns)...)
+	if err != nil {
+		return err
+	}
+
+	parseData := newData(prm.Top)
+	parseData.quantiles = prm.Quantiles
+	parseData.timeZone = prm.timeZone()
+	parseData.location = prm.location()
+	parseData.bucket = prm.Bucket
+	eg, egCtx := errgroup.WithContext(ctx)
+
+	lines := p.tail(egCtx, eg, &prm, patterns, &parseData)
+	p.process(egCtx, eg, &prm, filter, lines, &parseData)
+
+	ticker := time.NewTicker(renderInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+
+		case <-refresh:
+
+		case <-egCtx.Done():
+			if err := eg.Wait(); err != nil {
+				return fmt.Errorf("eg.Wait(): %w", err)
+			}
+
+			return reportData(&parseData, report)
+		}
+
+		if err := reportData(&parseData, report); err != nil {
+			cancel()
+
+			if waitErr := eg.Wait(); waitErr != nil {
+				slog.Error(fmt.Sprintf("eg.Wait(): %s", waitErr))
+			}
+
+			return err
+		}
+	}
+}
+
+func reportData(parseData *data, report func(info *domain.FileInfo) error) error {
+	if err := report(parseData.fileInfo()); err != nil {
+		return fmt.Errorf("report: %w", err)
+	}
+
+	return nil
+}
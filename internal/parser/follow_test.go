@@ -0,0 +1,301 @@
+package parser_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendFile(t *testing.T, path, content string) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err, "file must be opened")
+
+	_, err = f.WriteString(content)
+	require.NoError(t, err, "content must be written")
+	require.NoError(t, f.Close(), "file must be closed")
+}
+
+func TestFollow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	appendFile(t, path, logLine("10.0.0.1", "/a", 100))
+
+	var (
+		mu   sync.Mutex
+		last *domain.FileInfo
+	)
+
+	totalRequests := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if last == nil {
+			return 0
+		}
+
+		return last.TotalRequests
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() {
+		done <- parser.New().Follow(ctx, parser.Params{
+			Paths:          []string{filepath.Join(dir, "*.log")},
+			PollInterval:   10 * time.Millisecond,
+			RenderInterval: 10 * time.Millisecond,
+		}, nil, func(info *domain.FileInfo) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			last = info
+
+			return nil
+		})
+	}()
+
+	require.Eventually(t, func() bool { return totalRequests() == 1 }, time.Second, 10*time.Millisecond)
+
+	appendFile(t, path, logLine("10.0.0.1", "/a", 100)+logLine("10.0.0.2", "/b", 200))
+	require.Eventually(t, func() bool { return totalRequests() == 3 }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.Rename(path, path+".1"), "file must be rotated")
+	appendFile(t, path, logLine("10.0.0.3", "/c", 300))
+	require.Eventually(t, func() bool { return totalRequests() == 4 }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.Truncate(path, 0), "file must be truncated")
+	time.Sleep(50 * time.Millisecond)
+	appendFile(t, path, logLine("10.0.0.4", "/d", 400))
+	require.Eventually(t, func() bool { return totalRequests() == 5 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done, "follow must stop without error")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, 5, last.TotalRequests)
+	assert.Equal(t, 220, last.AvgResponseSize)
+	assert.Equal(t, []domain.SourceFormat{domain.NewSourceFormat(path, "combined")}, last.Formats)
+}
+
+type followReport struct {
+	mu   sync.Mutex
+	last *domain.FileInfo
+	done chan error
+	stop context.CancelFunc
+}
+
+func startFollow(prm parser.Params) *followReport {
+	ctx, cancel := context.WithCancel(context.Background())
+	fr := &followReport{
+		done: make(chan error),
+		stop: cancel,
+	}
+
+	prm.PollInterval = 10 * time.Millisecond
+	prm.RenderInterval = 10 * time.Millisecond
+
+	go func() {
+		fr.done <- parser.New().Follow(ctx, prm, nil, func(info *domain.FileInfo) error {
+			fr.mu.Lock()
+			defer fr.mu.Unlock()
+
+			fr.last = info
+
+			return nil
+		})
+	}()
+
+	return fr
+}
+
+func (fr *followReport) totalRequests() int {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	if fr.last == nil {
+		return 0
+	}
+
+	return fr.last.TotalRequests
+}
+
+func (fr *followReport) waitRequests(t *testing.T, count int) {
+	require.Eventually(t, func() bool { return fr.totalRequests() == count }, time.Second, 10*time.Millisecond)
+}
+
+func (fr *followReport) finish(t *testing.T) *domain.FileInfo {
+	fr.stop()
+	require.NoError(t, <-fr.done, "follow must stop without error")
+
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	return fr.last
+}
+
+func TestFollowRotatedGlob(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	appendFile(t, path, logLine("10.0.0.1", "/a", 100)+logLine("10.0.0.2", "/b", 200))
+
+	fr := startFollow(parser.Params{Paths: []string{path + "*"}})
+	fr.waitRequests(t, 2)
+
+	require.NoError(t, os.Rename(path, path+".1"), "file must be rotated")
+	appendFile(t, path, logLine("10.0.0.3", "/c", 300))
+	fr.waitRequests(t, 3)
+
+	appendFile(t, path+".1", logLine("10.0.0.4", "/d", 400))
+	fr.waitRequests(t, 4)
+
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, 4, fr.finish(t).TotalRequests, "rotated file must not be read again")
+}
+
+func TestFollowNginxConf(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "nginx.conf"), fmt.Sprintf(mainConf, dir))
+	writeFile(t, filepath.Join(dir, "conf.d", "api.conf"), serverConf)
+	writeFile(t, filepath.Join(dir, "logs", "access.log"), logLine("10.0.0.1", "/index.html", 100))
+	writeFile(t, filepath.Join(dir, "logs", "api.log"),
+		`10.0.0.2 - - [22/Oct/2024:09:48:45 +0000] "GET /api HTTP/1.1" 200 300 "-" "curl/8.0" 127.0.0.1:8080`+"\n")
+
+	fr := startFollow(parser.Params{
+		NginxConf: filepath.Join(dir, "nginx.conf"),
+		Filter:    "$upstream_addr != \"\"",
+	})
+	fr.waitRequests(t, 1)
+
+	appendFile(t, filepath.Join(dir, "logs", "json.log"),
+		`{"ip":"10.0.0.3","ts":"2024-10-22T09:48:45+00:00","request":"GET /v2 HTTP/1.1","status":200,"size":200}`+"\n")
+	appendFile(t, filepath.Join(dir, "logs", "api.log"),
+		`10.0.0.4 - - [22/Oct/2024:09:48:45 +0000] "GET /api HTTP/1.1" 200 500 "-" "curl/8.0" 127.0.0.1:8080`+"\n")
+	fr.waitRequests(t, 2)
+
+	info := fr.finish(t)
+
+	assert.Equal(t, 400, info.AvgResponseSize)
+	assert.Equal(t, []domain.SourceFormat{
+		domain.NewSourceFormat(filepath.Join(dir, "logs", "access.log"), "combined"),
+		domain.NewSourceFormat(filepath.Join(dir, "logs", "api.log"), "upstream"),
+		domain.NewSourceFormat(filepath.Join(dir, "logs", "json.log"), "json"),
+	}, info.Formats)
+}
+
+func TestFollowError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	appendFile(t, path, logLine("10.0.0.1", "/a", 100))
+
+	tt := []struct {
+		name string
+		prm  parser.Params
+		msg  string
+	}{
+		{
+			name: "url",
+			prm:  parser.Params{Paths: []string{"https://example.com/access.log"}},
+			msg:  `only local files can be followed, got "https://example.com/access.log"`,
+		},
+		{
+			name: "state file",
+			prm:  parser.Params{Paths: []string{path}, StateFile: filepath.Join(dir, "state.json")},
+			msg:  "state file can not be used in follow mode",
+		},
+		{
+			name: "max error percent",
+			prm:  parser.Params{Paths: []string{path}, ErrorPolicy: parser.ErrorPolicy{Mode: parser.Skip, MaxPercent: 10}},
+			msg:  "max error percent can not be used in follow mode",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := parser.New().Follow(context.Background(), tc.prm, nil, func(*domain.FileInfo) error { return nil })
+			require.ErrorAs(t, err, &parser.ErrFollow{})
+			assert.EqualError(t, err, tc.msg)
+		})
+	}
+}
+
+func TestFollowFilterUnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	appendFile(t, path, logLine("10.0.0.1", "/a", 100))
+
+	tt := []struct {
+		name      string
+		logFormat string
+		field     string
+		err       bool
+	}{
+		{
+			name:  "misspelled field of auto format",
+			field: "Agent",
+			err:   true,
+		},
+		{
+			name:  "variable of auto format",
+			field: "$upstream_addr",
+			err:   true,
+		},
+		{
+			name:      "any key of json format",
+			logFormat: "json",
+			field:     "request_id",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := parser.New().Follow(ctx, parser.Params{
+				Paths:       []string{path},
+				LogFormat:   tc.logFormat,
+				FilterField: tc.field,
+				FilterValue: "x",
+			}, nil, func(*domain.FileInfo) error { return nil })
+			if tc.err {
+				require.ErrorAs(t, err, &parser.ErrUnknownField{})
+				return
+			}
+
+			require.NoError(t, err, "filter field must be accepted")
+		})
+	}
+}
+
+func TestFollowCompressedRotations(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	appendFile(t, path, logLine("10.0.0.1", "/a", 100))
+	writeFile(t, path+".1.gz", string(gzipBytes(t, logLine("10.0.0.2", "/b", 200)+logLine("10.0.0.3", "/c", 300))))
+
+	fr := startFollow(parser.Params{Paths: []string{path + "*"}})
+	fr.waitRequests(t, 3)
+
+	writeFile(t, path+".2.gz", string(gzipBytes(t, logLine("10.0.0.4", "/d", 400))))
+	time.Sleep(50 * time.Millisecond)
+
+	appendFile(t, path, logLine("10.0.0.5", "/e", 500))
+	fr.waitRequests(t, 4)
+
+	info := fr.finish(t)
+
+	assert.Equal(t, 4, info.TotalRequests, "compressed rotation must not be read after start")
+	assert.Equal(t, 275, info.AvgResponseSize)
+	assert.Empty(t, info.Malformed)
+}
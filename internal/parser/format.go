@@ -0,0 +1,164 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const CombinedFormat = `$remote_addr - $remote_user [$time_local] ` +
+	`"$request" $status $body_bytes_sent "$http_referer" "$http_user_agent"`
+
+var variablePatterns = map[string]string{
+	"status":          `(\d{3})`,
+	"body_bytes_sent": `(\d+|-)`,
+	"bytes_sent":      `(\d+)`,
+	"request_length":  `(\d+)`,
+	"remote_addr":     `(\S+)`,
+	"time_local":      `(\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4})`,
+	"time_iso8601":    `(\S+)`,
+}
+
+const defaultVariablePattern = `(.*?)`
+
+type decoder interface {
+	decode(text string) (log, error)
+}
+
+type Format struct {
+	regex     *regexp.Regexp
+	variables []string
+}
+
+func isVariableChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func splitVariable(format string) (name, rest string, ok bool) {
+	if strings.HasPrefix(format, "{") {
+		end := strings.IndexByte(format, '}')
+		if end <= 1 {
+			return "", format, false
+		}
+
+		return format[1:end], format[end+1:], true
+	}
+
+	end := 0
+	for end < len(format) && isVariableChar(format[end]) {
+		end++
+	}
+
+	if end == 0 {
+		return "", format, false
+	}
+
+	return format[:end], format[end:], true
+}
+
+func trimFormatQuotes(format string) string {
+	format = strings.TrimSpace(format)
+	if len(format) >= 2 && format[0] == '\'' && format[len(format)-1] == '\'' {
+		return format[1 : len(format)-1]
+	}
+
+	return format
+}
+
+func CompileFormat(format string) (*Format, error) {
+	rest := trimFormatQuotes(format)
+	variables := make([]string, 0)
+
+	var pattern strings.Builder
+
+	pattern.WriteString("^")
+
+	for rest != "" {
+		dollar := strings.IndexByte(rest, '$')
+		if dollar == -1 {
+			pattern.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+
+		pattern.WriteString(regexp.QuoteMeta(rest[:dollar]))
+
+		name, tail, ok := splitVariable(rest[dollar+1:])
+		if !ok {
+			pattern.WriteString(regexp.QuoteMeta("$"))
+			rest = tail
+
+			continue
+		}
+
+		variablePattern, ok := variablePatterns[name]
+		if !ok {
+			variablePattern = defaultVariablePattern
+		}
+
+		pattern.WriteString(variablePattern)
+
+		variables = append(variables, name)
+		rest = tail
+	}
+
+	pattern.WriteString("$")
+
+	if len(variables) == 0 {
+		return nil, NewErrLogFormat(fmt.Sprintf("no variables in log format %q", format))
+	}
+
+	regex, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("compile log format %q: %w", format, err)
+	}
+
+	return &Format{
+		regex:     regex,
+		variables: variables,
+	}, nil
+}
+
+func MustCompileFormat(format string) *Format {
+	f, err := CompileFormat(format)
+	if err != nil {
+		panic(fmt.Sprintf("compile log format: %s", err))
+	}
+
+	return f
+}
+
+func compileDecoder(format, escape string, jsonKeys map[string]string) (decoder, error) {
+	trimmed := trimFormatQuotes(format)
+
+	switch {
+	case trimmed == JSONFormatName:
+		return NewJSONFormat(jsonKeys), nil
+
+	case escape == "json" || strings.HasPrefix(trimmed, "{"):
+		return CompileJSONFormat(trimmed, jsonKeys)
+
+	default:
+		return CompileFormat(trimmed)
+	}
+}
+
+func (f *Format) Variables() []string {
+	return f.variables
+}
+
+func (f *Format) decode(text string) (log, error) {
+	matches := f.regex.FindStringSubmatch(text)
+	if matches == nil {
+		return log{}, NewErrRegexp("failed to parse log line with regexp")
+	}
+
+	var logEntry log
+
+	for i, name := range f.variables {
+		if err := logEntry.setVariable(name, matches[i+1]); err != nil {
+			return log{}, err
+		}
+	}
+
+	return logEntry, nil
+}
@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"container/heap"
+)
+
+type heavyEntry struct {
+	Key string `json:"key"`
+	counter
+	Error int `json:"error"`
+}
+
+type spaceSaving struct {
+	Capacity int           `json:"capacity"`
+	Metric   TopMetric     `json:"metric"`
+	Entries  []*heavyEntry `json:"entries"`
+	index    map[string]int
+}
+
+func newSpaceSaving(capacity int, metric TopMetric) *spaceSaving {
+	return &spaceSaving{
+		Capacity: capacity,
+		Metric:   metric,
+		Entries:  make([]*heavyEntry, 0, capacity),
+		index:    make(map[string]int, capacity),
+	}
+}
+
+func (s *spaceSaving) Len() int {
+	return len(s.Entries)
+}
+
+func (s *spaceSaving) Less(i, j int) bool {
+	return s.Entries[i].value(s.Metric) < s.Entries[j].value(s.Metric)
+}
+
+func (s *spaceSaving) Swap(i, j int) {
+	s.Entries[i], s.Entries[j] = s.Entries[j], s.Entries[i]
+	s.index[s.Entries[i].Key] = i
+	s.index[s.Entries[j].Key] = j
+}
+
+func (s *spaceSaving) Push(x any) {
+	entry := x.(*heavyEntry)
+	s.index[entry.Key] = len(s.Entries)
+	s.Entries = append(s.Entries, entry)
+}
+
+func (s *spaceSaving) Pop() any {
+	last := s.Entries[len(s.Entries)-1]
+	s.Entries = s.Entries[:len(s.Entries)-1]
+	delete(s.index, last.Key)
+
+	return last
+}
+
+func weight(metric TopMetric, logEntry *log) int {
+	cnt := counter{}
+	cnt.add(logEntry)
+
+	return cnt.value(metric)
+}
+
+func (s *spaceSaving) add(key string, logEntry *log) {
+	if i, ok := s.index[key]; ok {
+		s.Entries[i].add(logEntry)
+		heap.Fix(s, i)
+
+		return
+	}
+
+	if len(s.Entries) < s.Capacity {
+		entry := &heavyEntry{Key: key}
+		entry.add(logEntry)
+		heap.Push(s, entry)
+
+		return
+	}
+
+	if weight(s.Metric, logEntry) == 0 {
+		return
+	}
+
+	smallest := s.Entries[0]
+	delete(s.index, smallest.Key)
+
+	smallest.Key = key
+	smallest.Error = smallest.value(s.Metric)
+	smallest.add(logEntry)
+
+	s.index[key] = 0
+	heap.Fix(s, 0)
+}
+
+func (s *spaceSaving) bound() int {
+	if len(s.Entries) < s.Capacity {
+		return 0
+	}
+
+	return s.Entries[0].value(s.Metric)
+}
+
+func (s *spaceSaving) counters() (map[string]*counter, map[string]int) {
+	counters := make(map[string]*counter, len(s.Entries))
+	errs := make(map[string]int, len(s.Entries))
+
+	for _, entry := range s.Entries {
+		counters[entry.Key] = &entry.counter
+		errs[entry.Key] = entry.Error
+	}
+
+	return counters, errs
+}
+
+func (s *spaceSaving) restore(other *spaceSaving) {
+	s.Entries = s.Entries[:0]
+	clear(s.index)
+
+	for _, entry := range other.Entries {
+		s.index[entry.Key] = len(s.Entries)
+		s.Entries = append(s.Entries, entry)
+	}
+
+	heap.Init(s)
+
+	for len(s.Entries) > s.Capacity {
+		heap.Pop(s)
+	}
+}
@@ -0,0 +1,144 @@
+package parser_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeavyHitters(t *testing.T) {
+	content := &strings.Builder{}
+	exact := make(map[string]int)
+
+	for i := 0; i < 2000; i++ {
+		url := fmt.Sprintf("/scan/%d", i)
+		if i%4 == 0 {
+			url = fmt.Sprintf("/hot/%d", i%3)
+		}
+
+		exact[url]++
+		content.WriteString(statusLine(fmt.Sprintf("10.0.%d.%d", i%7, i%250), url, 200, 10))
+	}
+
+	fileName := createTestFiles(t, content.String())
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	logParser := parser.New()
+
+	data, err := logParser.Parse(parser.Params{
+		Paths: []string{fileName},
+		Top: parser.TopOptions{
+			URLs:      3,
+			Addresses: 5,
+			Capacity:  50,
+		},
+	})
+	require.NoError(t, err, "file must be parsed")
+
+	require.NotNil(t, data.HeavyHitters, "approximate mode must be reported")
+	assert.Equal(t, 50, data.HeavyHitters.Capacity)
+	assert.LessOrEqual(t, data.HeavyHitters.URLBound, 2000/50)
+
+	require.Len(t, data.FrequentURLs, 3)
+
+	names := make([]string, 0, len(data.FrequentURLs))
+	for _, url := range data.FrequentURLs {
+		names = append(names, url.Name)
+
+		assert.GreaterOrEqual(t, url.Quantity, exact[url.Name], "count of %s must not be underestimated", url.Name)
+		assert.LessOrEqual(t, url.Quantity-url.MaxError, exact[url.Name], "error of %s must bound the estimate", url.Name)
+		assert.LessOrEqual(t, url.MaxError, data.HeavyHitters.URLBound)
+	}
+
+	assert.ElementsMatch(t, []string{"/hot/0", "/hot/1", "/hot/2"}, names)
+	require.Len(t, data.FrequentAddresses, 5)
+}
+
+func TestParseHeavyHittersCapacity(t *testing.T) {
+	content := statusLine("10.0.0.1", "/a", 200, 100) +
+		statusLine("10.0.0.1", "/a", 200, 100) +
+		statusLine("10.0.0.2", "/b", 500, 1000) +
+		statusLine("10.0.0.3", "/c", 404, 50)
+
+	tt := []struct {
+		name              string
+		top               parser.TopOptions
+		frequentURLs      []domain.URL
+		frequentAddresses []domain.Address
+		heavyHitters      *domain.HeavyHitters
+	}{
+		{
+			name: "capacity is not reached",
+			top: parser.TopOptions{
+				URLs:      -1,
+				Addresses: 1,
+				Capacity:  10,
+			},
+			frequentURLs: []domain.URL{
+				domain.NewURL("/a", 2),
+				domain.NewURL("/b", 1),
+				domain.NewURL("/c", 1),
+			},
+			frequentAddresses: []domain.Address{
+				domain.NewAddress("10.0.0.1", 2),
+			},
+			heavyHitters: domain.NewHeavyHitters(10, 0, 0),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fileName := createTestFiles(t, content)
+			defer deleteTestFiles(t, getRoot(fileName))
+
+			logParser := parser.New()
+
+			data, err := logParser.Parse(parser.Params{
+				Paths: []string{fileName},
+				Top:   tc.top,
+			})
+			require.NoError(t, err, "file must be parsed")
+
+			assert.Equal(t, tc.frequentURLs, data.FrequentURLs)
+			assert.Equal(t, tc.frequentAddresses, data.FrequentAddresses)
+			assert.Equal(t, tc.heavyHitters, data.HeavyHitters)
+		})
+	}
+}
+
+func TestParseHeavyHittersEviction(t *testing.T) {
+	content := statusLine("10.0.0.1", "/a", 200, 100) +
+		statusLine("10.0.0.1", "/a", 200, 100) +
+		statusLine("10.0.0.2", "/b", 500, 1000) +
+		statusLine("10.0.0.3", "/c", 404, 50)
+	exact := map[string]int{"/a": 200, "/b": 1000, "/c": 50}
+
+	fileName := createTestFiles(t, content)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	logParser := parser.New()
+
+	data, err := logParser.Parse(parser.Params{
+		Paths: []string{fileName},
+		Top: parser.TopOptions{
+			URLs:     -1,
+			Metric:   parser.TopByBytes,
+			Capacity: 1,
+		},
+	})
+	require.NoError(t, err, "file must be parsed")
+
+	require.Len(t, data.FrequentURLs, 1)
+
+	url := data.FrequentURLs[0]
+	assert.Equal(t, 4, url.Quantity)
+	assert.Equal(t, 1250, url.Bytes)
+	assert.LessOrEqual(t, url.Bytes-url.MaxError, exact[url.Name], "error must bound the estimate")
+	assert.GreaterOrEqual(t, url.Bytes, exact[url.Name], "bytes must not be underestimated")
+	assert.Equal(t, domain.NewHeavyHitters(1, 1250, 1250), data.HeavyHitters)
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"math"
+	"math/bits"
+)
+
+const (
+	hllPrecision    = 14
+	hllDayPrecision = 12
+
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+type hyperLogLog struct {
+	Registers []uint8 `json:"registers"`
+}
+
+func newHyperLogLog(precision int) *hyperLogLog {
+	return &hyperLogLog{
+		Registers: make([]uint8, 1<<precision),
+	}
+}
+
+func hashStrings(values ...string) uint64 {
+	hash := uint64(fnvOffset)
+
+	for i, value := range values {
+		if i != 0 {
+			hash *= fnvPrime
+		}
+
+		for j := 0; j < len(value); j++ {
+			hash ^= uint64(value[j])
+			hash *= fnvPrime
+		}
+	}
+
+	hash ^= hash >> 33
+	hash *= 0xff51afd7ed558ccd
+	hash ^= hash >> 33
+	hash *= 0xc4ceb9fe1a85ec53
+	hash ^= hash >> 33
+
+	return hash
+}
+
+func (h *hyperLogLog) add(values ...string) {
+	hash := hashStrings(values...)
+	precision := bits.TrailingZeros(uint(len(h.Registers)))
+
+	index := hash >> (64 - precision)
+	rank := uint8(bits.LeadingZeros64(hash<<precision|1<<(precision-1)) + 1)
+
+	if rank > h.Registers[index] {
+		h.Registers[index] = rank
+	}
+}
+
+func (h *hyperLogLog) merge(other *hyperLogLog) {
+	if len(other.Registers) != len(h.Registers) {
+		return
+	}
+
+	for i, rank := range other.Registers {
+		h.Registers[i] = max(h.Registers[i], rank)
+	}
+}
+
+func (h *hyperLogLog) count() int {
+	registers := float64(len(h.Registers))
+	alpha := 0.7213 / (1 + 1.079/registers)
+
+	sum, zeros := 0.0, 0
+	for _, rank := range h.Registers {
+		sum += math.Ldexp(1, -int(rank))
+
+		if rank == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha * registers * registers / sum
+	if estimate <= 2.5*registers && zeros != 0 {
+		estimate = registers * math.Log(registers/float64(zeros))
+	}
+
+	return int(math.Round(estimate))
+}
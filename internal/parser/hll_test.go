@@ -0,0 +1,84 @@
+package parser_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func userAgentLine(address, url, day, userAgent string) string {
+	return fmt.Sprintf(`%s - - [%s/Oct/2024:09:48:45 +0000] "GET %s HTTP/1.1" 200 10 "-" "%s"`+"\n",
+		address, day, url, userAgent)
+}
+
+func TestParseUniqueCounts(t *testing.T) {
+	content := userAgentLine("10.0.0.1", "/a", "22", "curl/8.0") +
+		userAgentLine("10.0.0.1", "/a", "22", "Mozilla/5.0") +
+		userAgentLine("10.0.0.2", "/b", "22", "curl/8.0") +
+		userAgentLine("10.0.0.2", "/b", "22", "curl/8.0") +
+		userAgentLine("10.0.0.3", "/a", "23", "curl/8.0") +
+		userAgentLine("10.0.0.1", "/c", "23", "curl/8.0")
+
+	fileName := createTestFiles(t, content)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	logParser := parser.New()
+
+	data, err := logParser.Parse(parser.Params{
+		Paths: []string{fileName},
+	})
+	require.NoError(t, err, "file must be parsed")
+
+	assert.Equal(t, 3, data.UniqueAddresses)
+	assert.Equal(t, 4, data.UniqueVisitors)
+	assert.Equal(t, 3, data.UniqueURLs)
+	assert.Equal(t, []domain.DayRequests{
+		{Day: "2024-10-22", Quantity: 4, UniqueAddresses: 2},
+		{Day: "2024-10-23", Quantity: 2, UniqueAddresses: 2},
+	}, data.RequestsPerDay)
+}
+
+func TestParseUniqueCountsEstimate(t *testing.T) {
+	tt := []struct {
+		name      string
+		addresses int
+	}{
+		{
+			name:      "linear counting range",
+			addresses: 1000,
+		},
+		{
+			name:      "estimate range",
+			addresses: 100_000,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			content := &strings.Builder{}
+			for i := 0; i < tc.addresses; i++ {
+				content.WriteString(userAgentLine(fmt.Sprintf("10.%d.%d.%d", i>>16, i>>8&0xff, i&0xff), "/", "22", "curl/8.0"))
+			}
+
+			fileName := createTestFiles(t, content.String())
+			defer deleteTestFiles(t, getRoot(fileName))
+
+			logParser := parser.New()
+
+			data, err := logParser.Parse(parser.Params{
+				Paths: []string{fileName},
+			})
+			require.NoError(t, err, "file must be parsed")
+
+			assert.InEpsilon(t, tc.addresses, data.UniqueAddresses, 0.03)
+			assert.InEpsilon(t, tc.addresses, data.UniqueVisitors, 0.03)
+			assert.InEpsilon(t, tc.addresses, data.RequestsPerDay[0].UniqueAddresses, 0.05)
+			assert.Equal(t, 1, data.UniqueURLs)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const defaultHTTPTimeout = 30 * time.Second
+
+type HTTPOptions struct {
+	Timeout      time.Duration
+	Retries      int
+	RetryBackoff time.Duration
+	BearerToken  string
+	Username     string
+	Password     string
+	Headers      http.Header
+}
+
+type httpClient struct {
+	client  *http.Client
+	opts    HTTPOptions
+	timeout time.Duration
+}
+
+func newHTTPClient(opts HTTPOptions) *httpClient {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout: timeout,
+		}).DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		DisableCompression:    true,
+	}
+
+	return &httpClient{
+		client: &http.Client{
+			Transport: transport,
+		},
+		opts:    opts,
+		timeout: timeout,
+	}
+}
+
+func (c *httpClient) newRequest(ctx context.Context, url string, offset int64) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	for key, values := range c.opts.Headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	switch {
+	case c.opts.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.opts.BearerToken)
+
+	case c.opts.Username != "":
+		req.SetBasicAuth(c.opts.Username, c.opts.Password)
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	return req, nil
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+func (c *httpClient) backoff(attempt int) time.Duration {
+	return c.opts.RetryBackoff * time.Duration(1<<attempt)
+}
+
+func (c *httpClient) do(url string, offset int64) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	req, err := c.newRequest(ctx, url, offset)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("get file from url: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		closeResource(resp.Body)
+		cancel()
+
+		return nil, NewErrHTTPStatus(resp.StatusCode, fmt.Sprintf("get %s: unexpected status %s", url, resp.Status))
+	}
+
+	resp.Body = newIdleBody(resp.Body, c.timeout, cancel)
+
+	return resp, nil
+}
+
+func (c *httpClient) get(url string, offset int64) (*http.Response, error) {
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		var resp *http.Response
+
+		resp, err = c.do(url, offset)
+		if err == nil {
+			return resp, nil
+		}
+
+		var errStatus ErrHTTPStatus
+		if errors.As(err, &errStatus) && !retryableStatus(errStatus.Code) || attempt >= c.opts.Retries {
+			return nil, err
+		}
+
+		slog.Warn(fmt.Sprintf("attempt %d: %s", attempt+1, err))
+		time.Sleep(c.backoff(attempt))
+	}
+}
+
+type idleBody struct {
+	body    io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	cancel  context.CancelFunc
+	expired atomic.Bool
+}
+
+func newIdleBody(body io.ReadCloser, timeout time.Duration, cancel context.CancelFunc) *idleBody {
+	b := &idleBody{
+		body:    body,
+		timeout: timeout,
+		cancel:  cancel,
+	}
+
+	b.timer = time.AfterFunc(timeout, func() {
+		b.expired.Store(true)
+		cancel()
+	})
+	b.timer.Stop()
+
+	return b
+}
+
+func (b *idleBody) Read(p []byte) (int, error) {
+	b.timer.Reset(b.timeout)
+	n, err := b.body.Read(p)
+	b.timer.Stop()
+
+	if err != nil && b.expired.Load() {
+		return n, NewErrHTTPTimeout(fmt.Sprintf("no data received for %s", b.timeout))
+	}
+
+	return n, err //nolint:wrapcheck // io.EOF must not be wrapped
+}
+
+func (b *idleBody) Close() error {
+	b.timer.Stop()
+	defer b.cancel()
+
+	return b.body.Close()
+}
+
+type resumableBody struct {
+	client  *httpClient
+	url     string
+	body    io.ReadCloser
+	offset  int64
+	resumes int
+}
+
+func (b *resumableBody) resume() error {
+	closeResource(b.body)
+
+	resp, err := b.client.get(b.url, b.offset)
+	if err != nil {
+		return err
+	}
+
+	b.body = resp.Body
+
+	if resp.StatusCode == http.StatusPartialContent {
+		return nil
+	}
+
+	if _, err := io.CopyN(io.Discard, resp.Body, b.offset); err != nil {
+		return fmt.Errorf("skip %d already read bytes: %w", b.offset, err)
+	}
+
+	return nil
+}
+
+func (b *resumableBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.offset += int64(n)
+
+	if err == nil || errors.Is(err, io.EOF) || b.resumes >= b.client.opts.Retries {
+		return n, err //nolint:wrapcheck // io.EOF must not be wrapped
+	}
+
+	slog.Warn(fmt.Sprintf("resume %s from byte %d: %s", b.url, b.offset, err))
+
+	time.Sleep(b.client.backoff(b.resumes))
+	b.resumes++
+
+	if err := b.resume(); err != nil {
+		return n, fmt.Errorf("resume download: %w", err)
+	}
+
+	return n, nil
+}
+
+func (b *resumableBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,277 @@
+package parser_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURLStatusError(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			http.Error(w, "<html>not found</html>", http.StatusNotFound)
+		}),
+	)
+	defer server.Close()
+
+	logParser := parser.New()
+
+	_, err := logParser.Parse(parser.Params{
+		Paths: []string{server.URL},
+		HTTP: parser.HTTPOptions{
+			Retries: 3,
+		},
+	})
+
+	var errStatus parser.ErrHTTPStatus
+
+	require.ErrorAs(t, err, &errStatus, "404 must be rejected")
+	assert.Equal(t, http.StatusNotFound, errStatus.Code)
+	assert.Equal(t, int32(1), requests.Load(), "client errors must not be retried")
+}
+
+func TestParseURLRetry(t *testing.T) {
+	tt := []struct {
+		name     string
+		failures int32
+		retries  int
+		wantErr  bool
+	}{
+		{
+			name:     "recovered after retries",
+			failures: 2,
+			retries:  2,
+		},
+		{
+			name:     "out of retries",
+			failures: 3,
+			retries:  2,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests atomic.Int32
+
+			server := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					if requests.Add(1) <= tc.failures {
+						w.WriteHeader(http.StatusServiceUnavailable)
+						return
+					}
+
+					fmt.Fprint(w, logLine("10.0.0.1", "/a", 100))
+				}),
+			)
+			defer server.Close()
+
+			logParser := parser.New()
+
+			data, err := logParser.Parse(parser.Params{
+				Paths: []string{server.URL},
+				HTTP: parser.HTTPOptions{
+					Retries:      tc.retries,
+					RetryBackoff: time.Millisecond,
+				},
+			})
+			if tc.wantErr {
+				var errStatus parser.ErrHTTPStatus
+
+				require.ErrorAs(t, err, &errStatus, "last status must be returned")
+				assert.Equal(t, http.StatusServiceUnavailable, errStatus.Code)
+
+				return
+			}
+
+			require.NoError(t, err, "url must be parsed after retries")
+			assert.Equal(t, 1, data.TotalRequests)
+		})
+	}
+}
+
+func TestParseURLAuth(t *testing.T) {
+	tt := []struct {
+		name   string
+		opts   parser.HTTPOptions
+		header string
+		want   string
+	}{
+		{
+			name:   "bearer",
+			opts:   parser.HTTPOptions{BearerToken: "secret"},
+			header: "Authorization",
+			want:   "Bearer secret",
+		},
+		{
+			name:   "basic",
+			opts:   parser.HTTPOptions{Username: "user", Password: "pass"},
+			header: "Authorization",
+			want:   "Basic dXNlcjpwYXNz",
+		},
+		{
+			name:   "custom header",
+			opts:   parser.HTTPOptions{Headers: http.Header{"X-Api-Key": {"key"}}},
+			header: "X-Api-Key",
+			want:   "key",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Header.Get(tc.header) != tc.want {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+
+					fmt.Fprint(w, logLine("10.0.0.1", "/a", 100))
+				}),
+			)
+			defer server.Close()
+
+			logParser := parser.New()
+
+			data, err := logParser.Parse(parser.Params{
+				Paths: []string{server.URL},
+				HTTP:  tc.opts,
+			})
+			require.NoError(t, err, "authorized url must be parsed")
+			assert.Equal(t, 1, data.TotalRequests)
+		})
+	}
+}
+
+func TestParseURLResume(t *testing.T) {
+	body := strings.Repeat(logLine("10.0.0.1", "/a", 100), 50)
+	half := len(body) / 2
+
+	var ranges []string
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rng := r.Header.Get("Range")
+			ranges = append(ranges, rng)
+
+			if rng == "" {
+				w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				_, _ = w.Write([]byte(body[:half]))
+				w.(http.Flusher).Flush()
+
+				panic(http.ErrAbortHandler)
+			}
+
+			offset, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			if err != nil {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(body[offset:]))
+		}),
+	)
+	defer server.Close()
+
+	logParser := parser.New()
+
+	data, err := logParser.Parse(parser.Params{
+		Paths: []string{server.URL},
+		HTTP: parser.HTTPOptions{
+			Retries:      1,
+			RetryBackoff: time.Millisecond,
+		},
+	})
+	require.NoError(t, err, "dropped download must be resumed")
+
+	assert.Equal(t, 50, data.TotalRequests)
+	require.Len(t, ranges, 2)
+	assert.Equal(t, "bytes="+strconv.Itoa(half)+"-", ranges[1])
+}
+
+func TestParseURLResumeStalled(t *testing.T) {
+	body := strings.Repeat(logLine("10.0.0.1", "/a", 100), 50)
+	half := len(body) / 2
+
+	var ranges []string
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rng := r.Header.Get("Range")
+			ranges = append(ranges, rng)
+
+			if rng == "" {
+				w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				_, _ = w.Write([]byte(body[:half]))
+				w.(http.Flusher).Flush()
+
+				<-r.Context().Done()
+
+				return
+			}
+
+			offset, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			if err != nil {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(body[offset:]))
+		}),
+	)
+	defer server.Close()
+
+	tt := []struct {
+		name    string
+		retries int
+		err     bool
+	}{
+		{
+			name:    "resumed",
+			retries: 1,
+		},
+		{
+			name: "no retries",
+			err:  true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ranges = nil
+
+			data, err := parser.New().Parse(parser.Params{
+				Paths: []string{server.URL},
+				HTTP: parser.HTTPOptions{
+					Timeout:      50 * time.Millisecond,
+					Retries:      tc.retries,
+					RetryBackoff: time.Millisecond,
+				},
+			})
+			if tc.err {
+				require.ErrorAs(t, err, &parser.ErrHTTPTimeout{})
+				return
+			}
+
+			require.NoError(t, err, "stalled download must be resumed")
+
+			assert.Equal(t, 50, data.TotalRequests)
+			require.Len(t, ranges, 2)
+			assert.Equal(t, "bytes="+strconv.Itoa(half)+"-", ranges[1])
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const JSONFormatName = "json"
+
+type JSONFormat struct {
+	keys map[string]string
+}
+
+func NewJSONFormat(keys map[string]string) *JSONFormat {
+	mapping := make(map[string]string, len(keys))
+	for key, variable := range keys {
+		mapping[key] = strings.TrimPrefix(variable, "$")
+	}
+
+	return &JSONFormat{
+		keys: mapping,
+	}
+}
+
+var jsonTemplateRegexp = regexp.MustCompile(`"([^"]+)"\s*:\s*"?\$\{?(\w+)\}?"?\s*[,}]`)
+
+func CompileJSONFormat(template string, keys map[string]string) (*JSONFormat, error) {
+	template = strings.TrimSpace(trimFormatQuotes(template))
+	if !strings.HasPrefix(template, "{") || !strings.HasSuffix(template, "}") {
+		return nil, NewErrLogFormat(fmt.Sprintf("json log format %q is not an object", template))
+	}
+
+	matches := jsonTemplateRegexp.FindAllStringSubmatch(template, -1)
+	mapping := make(map[string]string, len(matches)+len(keys))
+
+	for _, match := range matches {
+		mapping[match[1]] = match[2]
+	}
+
+	for key, variable := range keys {
+		mapping[key] = strings.TrimPrefix(variable, "$")
+	}
+
+	return &JSONFormat{
+		keys: mapping,
+	}, nil
+}
+
+func (f *JSONFormat) variable(key string) string {
+	if name, ok := f.keys[key]; ok {
+		return name
+	}
+
+	return key
+}
+
+func jsonValueToString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+
+	case json.Number:
+		return v.String(), true
+
+	case bool:
+		return fmt.Sprintf("%t", v), true
+
+	case nil:
+		return "", true
+	}
+
+	return "", false
+}
+
+func (f *JSONFormat) decode(text string) (log, error) {
+	values := make(map[string]any)
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(text)))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&values); err != nil {
+		return log{}, NewErrJSON(fmt.Sprintf("failed to parse log line as json: %s", err))
+	}
+
+	var logEntry log
+
+	for key, value := range values {
+		str, ok := jsonValueToString(value)
+		if !ok {
+			continue
+		}
+
+		if err := logEntry.setVariable(f.variable(key), str); err != nil {
+			return log{}, err
+		}
+	}
+
+	if logEntry.TimeLocal.IsZero() {
+		return log{}, NewErrMissingTime("json log line has no time_local or time_iso8601 value")
+	}
+
+	return logEntry, nil
+}
@@ -1,13 +1,70 @@
 package parser
 
+import (
+	"bufio"
+	"bytes"
+)
+
+const (
+	lineBufferSize = 64 * 1024
+	maxLineLength  = 1024 * 1024
+)
+
 type line struct {
 	text   string
 	number int
+	source string
+	format decoder
+	err    error
 }
 
-func newLine(text string, number int) line {
+func newLine(text string, number int, source string, format decoder) line {
 	return line{
 		text:   text,
 		number: number,
+		source: source,
+		format: format,
+	}
+}
+
+func (l *line) decode() (log, error) {
+	if l.err != nil {
+		return log{}, l.err
+	}
+
+	return l.format.decode(l.text)
+}
+
+type lineSplitter struct {
+	skipping bool
+	tooLong  bool
+}
+
+func (s *lineSplitter) split(data []byte, atEOF bool) (int, []byte, error) {
+	if !s.skipping {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if token != nil || advance > 0 || len(data) < maxLineLength {
+			if token != nil {
+				s.tooLong = false
+			}
+
+			return advance, token, err
+		}
+
+		s.skipping = true
 	}
+
+	end := bytes.IndexByte(data, '\n')
+
+	switch {
+	case end >= 0:
+		s.skipping, s.tooLong = false, true
+		return end + 1, data[:0], nil
+
+	case atEOF:
+		s.skipping, s.tooLong = false, true
+		return len(data), data[:0], nil
+	}
+
+	return len(data), nil, nil
 }
@@ -1,6 +1,15 @@
 package parser
 
-import "time"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+)
+
+const timeLocalLayout = "02/Jan/2006:15:04:05 -0700"
 
 type log struct {
 	RemoteAddress string
@@ -13,4 +22,98 @@ type log struct {
 	BodyBytesSend int
 	Referer       string
 	UserAgent     string
+	Fields        map[string]string
+}
+
+func (l *log) setRequest(request string) error {
+	parts := strings.Split(request, " ")
+	if len(parts) != 3 {
+		return NewErrRegexp("failed to parse request")
+	}
+
+	l.Method = parts[0]
+	l.URL = parts[1]
+	l.HTTPVersion = parts[2]
+
+	return nil
+}
+
+func (l *log) setStatus(value string) error {
+	status, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("failed to parse status: %w", err)
+	}
+
+	if !domain.ValidStatus(status) {
+		return NewErrBadStatus("no such status")
+	}
+
+	l.Status = status
+
+	return nil
+}
+
+func (l *log) setVariable(name, value string) error {
+	var err error
+
+	switch name {
+	case "remote_addr":
+		l.RemoteAddress = value
+
+	case "remote_user":
+		l.RemoteUser = value
+
+	case "time_local":
+		l.TimeLocal, err = time.Parse(timeLocalLayout, value)
+		if err != nil {
+			return fmt.Errorf("failed to parse time: %w", err)
+		}
+
+	case "time_iso8601":
+		l.TimeLocal, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("failed to parse time: %w", err)
+		}
+
+	case "request":
+		return l.setRequest(value)
+
+	case "request_method":
+		l.Method = value
+
+	case "request_uri":
+		l.URL = value
+
+	case "server_protocol":
+		l.HTTPVersion = value
+
+	case "status":
+		return l.setStatus(value)
+
+	case "body_bytes_sent":
+		if value == "-" {
+			l.BodyBytesSend = 0
+			return nil
+		}
+
+		l.BodyBytesSend, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse bodyBytesSend: %w", err)
+		}
+
+	case "http_referer":
+		l.Referer = value
+
+	case "http_user_agent":
+		l.UserAgent = value
+
+	default:
+		if l.Fields == nil {
+			l.Fields = make(map[string]string)
+		}
+
+		l.Fields[name] = value
+	}
+
+	return nil
 }
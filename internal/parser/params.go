@@ -1,11 +1,48 @@
 package parser
 
-import "time"
+import (
+	"io"
+	"time"
+)
+
+const StdinPath = "-"
 
 type Params struct {
-	Path        string
+	Paths       []string
+	Stdin       io.Reader
+	HTTP        HTTPOptions
+	NginxConf   string
+	LogFormat   string
+	JSONKeys    map[string]string
+	DetectLines int
 	From        *time.Time
 	To          *time.Time
 	FilterField string
 	FilterValue string
+	Filter      string
+	ErrorPolicy ErrorPolicy
+	Top         TopOptions
+	Quantiles   []float64
+	StateFile   string
+	TimeZone    *time.Location
+	Bucket      time.Duration
+
+	PollInterval   time.Duration
+	RenderInterval time.Duration
+}
+
+func (p *Params) timeZone() string {
+	if p.TimeZone == nil {
+		return ""
+	}
+
+	return p.TimeZone.String()
+}
+
+func (p *Params) location() *time.Location {
+	if p.TimeZone == nil {
+		return time.UTC
+	}
+
+	return p.TimeZone
 }
@@ -2,19 +2,15 @@ package parser
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
-	"net/http"
+	"math/bits"
 	"net/url"
 	"os"
-	"path/filepath"
-	"reflect"
-	"regexp"
 	"sort"
-	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -73,91 +69,247 @@ func getFiles(paths []string) ([]*os.File, error) {
 	return files, nil
 }
 
-func get95p[T ~int](sl []T) T {
-	sort.Slice(sl, func(i, j int) bool {
-		return sl[i] < sl[j]
-	})
+func withMetric(metric TopMetric, cnt *counter) (int, int) {
+	switch metric {
+	case TopByBytes:
+		return cnt.Bytes, 0
+
+	case TopByErrors:
+		return 0, cnt.Errors
 
-	return sl[95*len(sl)/100]
+	default:
+		return 0, 0
+	}
 }
 
-const frequencyLimit = 3
+func heavyCounters(counters map[string]*counter, sketch *spaceSaving) (map[string]*counter, map[string]int) {
+	if sketch == nil {
+		return counters, nil
+	}
 
-func frequentURLs(parseData *data) []domain.URL {
-	frequentURLs := make([]domain.URL, 0, len(parseData.urls))
-	for url, quantity := range parseData.urls {
-		frequentURLs = append(frequentURLs, domain.NewURL(url, quantity))
+	return sketch.counters()
+}
+
+func heavyHitters(parseData *data) *domain.HeavyHitters {
+	if parseData.urlSketch == nil {
+		return nil
 	}
 
-	sort.Slice(frequentURLs, func(i, j int) bool {
-		if frequentURLs[i].Quantity != frequentURLs[j].Quantity {
-			return frequentURLs[i].Quantity > frequentURLs[j].Quantity
-		}
+	return domain.NewHeavyHitters(
+		parseData.top.Capacity,
+		parseData.urlSketch.bound(),
+		parseData.addressSketch.bound(),
+	)
+}
 
-		return frequentURLs[i].Name < frequentURLs[j].Name
-	})
+func frequentURLs(parseData *data) []domain.URL {
+	counters, maxErrors := heavyCounters(parseData.urls, parseData.urlSketch)
+	top, other := topEntries(counters, parseData.top.URLs, &parseData.top)
+	metric := parseData.top.metric()
+
+	frequentURLs := make([]domain.URL, 0, len(top)+1)
+	for i := range top {
+		url := domain.NewURL(top[i].key, top[i].Requests)
+		url.Bytes, url.Errors = withMetric(metric, &top[i].counter)
+		url.MaxError = maxErrors[top[i].key]
+		frequentURLs = append(frequentURLs, url)
+	}
 
-	urlLimit := min(frequencyLimit, len(frequentURLs))
-	frequentURLs = frequentURLs[:urlLimit]
+	if other != nil {
+		url := domain.NewURL(domain.OtherName, other.Requests)
+		url.Bytes, url.Errors = withMetric(metric, other)
+		frequentURLs = append(frequentURLs, url)
+	}
 
 	return frequentURLs
 }
 
 func frequentStatuses(parseData *data) []domain.Status {
-	frequentStatuses := make([]domain.Status, 0, len(parseData.urls))
-	for status, quantity := range parseData.statuses {
-		frequentStatuses = append(
-			frequentStatuses,
-			domain.NewStatus(status, quantity),
-		)
+	top, other := topEntries(parseData.statuses, parseData.top.Statuses, &parseData.top)
+	metric := parseData.top.metric()
+
+	frequentStatuses := make([]domain.Status, 0, len(top)+1)
+	for i := range top {
+		status := domain.NewStatus(top[i].key, top[i].Requests)
+		status.Bytes, status.Errors = withMetric(metric, &top[i].counter)
+		frequentStatuses = append(frequentStatuses, status)
 	}
 
-	sort.Slice(frequentStatuses, func(i, j int) bool {
-		if frequentStatuses[i].Quantity != frequentStatuses[j].Quantity {
-			return frequentStatuses[i].Quantity > frequentStatuses[j].Quantity
-		}
+	if other != nil {
+		status := domain.NewOtherStatus(other.Requests)
+		status.Bytes, status.Errors = withMetric(metric, other)
+		frequentStatuses = append(frequentStatuses, status)
+	}
+
+	return frequentStatuses
+}
+
+func frequentAddresses(parseData *data) []domain.Address {
+	counters, maxErrors := heavyCounters(parseData.addresses, parseData.addressSketch)
+	top, other := topEntries(counters, parseData.top.Addresses, &parseData.top)
+	metric := parseData.top.metric()
+
+	frequentAddresses := make([]domain.Address, 0, len(top)+1)
+	for i := range top {
+		address := domain.NewAddress(top[i].key, top[i].Requests)
+		address.Bytes, address.Errors = withMetric(metric, &top[i].counter)
+		address.MaxError = maxErrors[top[i].key]
+		frequentAddresses = append(frequentAddresses, address)
+	}
+
+	if other != nil {
+		address := domain.NewAddress(domain.OtherName, other.Requests)
+		address.Bytes, address.Errors = withMetric(metric, other)
+		frequentAddresses = append(frequentAddresses, address)
+	}
+
+	return frequentAddresses
+}
+
+func allStatuses(parseData *data) []domain.Status {
+	statuses := make([]domain.Status, 0, len(parseData.statuses))
+	for status, cnt := range parseData.statuses {
+		statuses = append(statuses, domain.NewStatus(status, cnt.Requests))
+	}
 
-		return frequentStatuses[i].Code < frequentStatuses[j].Code
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Code < statuses[j].Code
 	})
 
-	statusLimit := min(frequencyLimit, len(frequentStatuses))
-	frequentStatuses = frequentStatuses[:statusLimit]
+	return statuses
+}
 
-	return frequentStatuses
+func methods(parseData *data) []domain.Method {
+	methods := make([]domain.Method, 0, len(parseData.methods))
+	for method, quantity := range parseData.methods {
+		methods = append(methods, domain.NewMethod(method, quantity))
+	}
+
+	sort.Slice(methods, func(i, j int) bool {
+		if methods[i].Quantity != methods[j].Quantity {
+			return methods[i].Quantity > methods[j].Quantity
+		}
+
+		return methods[i].Name < methods[j].Name
+	})
+
+	return methods
 }
 
-func frequentAddresses(parseData *data) []domain.Address {
-	frequentAddresses := make([]domain.Address, 0, len(parseData.addresses))
-	for ip, quantity := range parseData.addresses {
-		frequentAddresses = append(
-			frequentAddresses,
-			domain.NewAddress(ip, quantity),
-		)
+func sourceFormats(parseData *data) []domain.SourceFormat {
+	formats := make([]domain.SourceFormat, len(parseData.paths))
+	for i, path := range parseData.paths {
+		formats[i] = domain.NewSourceFormat(path, parseData.formats[i])
 	}
 
-	sort.Slice(frequentAddresses, func(i, j int) bool {
-		if frequentAddresses[i].Quantity != frequentAddresses[j].Quantity {
-			return frequentAddresses[i].Quantity > frequentAddresses[j].Quantity
+	return formats
+}
+
+func requestsPerDay(parseData *data) []domain.DayRequests {
+	days := make([]time.Time, 0, len(parseData.requestsPerDay))
+	for day := range parseData.requestsPerDay {
+		tm, err := time.Parse(timeLayout, day)
+		if err != nil {
+			continue
 		}
 
-		return frequentAddresses[i].Name < frequentAddresses[j].Name
+		days = append(days, tm)
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
 	})
 
-	addressLimit := min(frequencyLimit, len(frequentAddresses))
-	frequentAddresses = frequentAddresses[:addressLimit]
+	requests := make([]domain.DayRequests, len(days))
+	for i, day := range days {
+		key := day.Format(timeLayout)
 
-	return frequentAddresses
+		requests[i] = domain.NewDayRequests(day.Format(time.DateOnly), parseData.requestsPerDay[key])
+		if addresses, ok := parseData.dayAddresses[key]; ok {
+			requests[i].UniqueAddresses = addresses.count()
+		}
+	}
+
+	return requests
+}
+
+func statusClasses(parseData *data) []domain.StatusClass {
+	var quantities [6]int
+	for status, cnt := range parseData.statuses {
+		quantities[min(status/100, 5)] += cnt.Requests
+	}
+
+	classes := make([]domain.StatusClass, 0, len(quantities))
+
+	for class, quantity := range quantities {
+		if quantity != 0 {
+			classes = append(classes, domain.NewStatusClass(fmt.Sprintf("%dxx", class), quantity))
+		}
+	}
+
+	return classes
+}
+
+func sizeHistogram(sizes *sizeSketch) []domain.SizeBucket {
+	buckets := sizes.buckets()
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	quantities := make(map[int]int)
+	for _, bucket := range buckets {
+		quantities[bits.Len(uint(sketchLowest(bucket.index)))] += bucket.count
+	}
+
+	first := bits.Len(uint(sketchLowest(buckets[0].index)))
+	last := bits.Len(uint(sketchLowest(buckets[len(buckets)-1].index)))
+
+	histogram := make([]domain.SizeBucket, 0, last-first+1)
+
+	for bucket := first; bucket <= last; bucket++ {
+		from := 0
+		if bucket != 0 {
+			from = 1 << (bucket - 1)
+		}
+
+		histogram = append(histogram, domain.NewSizeBucket(from, 1<<bucket, quantities[bucket]))
+	}
+
+	return histogram
+}
+
+func sizeQuantiles(parseData *data) []domain.Quantile {
+	qs := parseData.quantiles
+	if qs == nil {
+		qs = DefaultQuantiles
+	}
+
+	values := parseData.sizes.quantiles(qs)
+
+	quantiles := make([]domain.Quantile, len(qs))
+	for i, q := range qs {
+		quantiles[i] = domain.NewQuantile(q, values[i])
+	}
+
+	return quantiles
 }
 
 func dataToFileInfo(parseData *data) *domain.FileInfo {
 	if parseData.totalRequests == 0 {
 		return &domain.FileInfo{
-			Paths: parseData.paths,
+			Paths:      parseData.paths,
+			Formats:    sourceFormats(parseData),
+			TotalLines: parseData.totalLines,
+			TopMetric:  string(parseData.top.metric()),
+			Malformed:  malformedToDomain(parseData.malformed),
+			TimeZone:   parseData.timeZone,
+
+			HeavyHitters: heavyHitters(parseData),
 		}
 	}
 
 	avgResponseSize := parseData.sizeSum / parseData.totalRequests
-	responseSize95p := get95p(parseData.sizeSlice)
+	responseSize95p := parseData.sizes.quantile(0.95)
 
 	freqURLs := frequentURLs(parseData)
 	freqStatuses := frequentStatuses(parseData)
@@ -168,9 +320,11 @@ func dataToFileInfo(parseData *data) *domain.FileInfo {
 		avgResponsesPerDay += quantity
 	}
 
-	avgResponsesPerDay /= len(parseData.requestsPerDay)
+	if len(parseData.requestsPerDay) != 0 {
+		avgResponsesPerDay /= len(parseData.requestsPerDay)
+	}
 
-	return domain.NewFileInfo(
+	fileInfo := domain.NewFileInfo(
 		parseData.paths,
 		parseData.totalRequests,
 		avgResponseSize,
@@ -180,61 +334,53 @@ func dataToFileInfo(parseData *data) *domain.FileInfo {
 		freqStatuses,
 		freqAddresses,
 	)
+	fileInfo.Formats = sourceFormats(parseData)
+	fileInfo.TotalLines = parseData.totalLines
+	fileInfo.Malformed = malformedToDomain(parseData.malformed)
+	fileInfo.RequestsPerDay = requestsPerDay(parseData)
+	fileInfo.StatusClasses = statusClasses(parseData)
+	fileInfo.SizeHistogram = sizeHistogram(parseData.sizes)
+	fileInfo.ResponseSizeQuantiles = sizeQuantiles(parseData)
+	fileInfo.ResponseSizeMax = parseData.sizes.Max
+	fileInfo.ResponseSizeSum = parseData.sizeSum
+	fileInfo.Statuses = allStatuses(parseData)
+	fileInfo.Methods = methods(parseData)
+	fileInfo.TopMetric = string(parseData.top.metric())
+	fileInfo.TimeZone = parseData.timeZone
+	fileInfo.TimeSeries = timeSeries(parseData)
+	fileInfo.HeavyHitters = heavyHitters(parseData)
+	fileInfo.UniqueAddresses = parseData.uniqueAddrs.count()
+	fileInfo.UniqueVisitors = parseData.uniqueVisitors.count()
+	fileInfo.UniqueURLs = parseData.uniqueURLs.count()
+
+	return fileInfo
 }
 
 type Parser struct {
-	regex      *regexp.Regexp
-	timeLayout string
+	format decoder
 }
 
 func New() *Parser {
-	regex := regexp.MustCompile(
-		`^(\S+) - (\S+) \[([^\]]+)\] "(\S+) (\S+) (\S+)" (\d+) (\d+) "([^"]+)" "([^"]*)"$`,
-	)
-
 	return &Parser{
-		regex:      regex,
-		timeLayout: "02/Jan/2006:15:04:05 -0700",
+		format: MustCompileFormat(CombinedFormat),
 	}
 }
 
-func (p *Parser) lineToLog(line string) (log, error) {
-	matches := p.regex.FindStringSubmatch(line)
-	if matches == nil {
-		return log{}, NewErrRegexp("failed to parse log line with regexp")
-	}
+func (p *Parser) logFormat(prm *Params) (decoder, string, error) {
+	switch prm.LogFormat {
+	case "", AutoFormatName:
+		return nil, AutoFormatName, nil
 
-	parsedTime, err := time.Parse(p.timeLayout, matches[3])
-	if err != nil {
-		return log{}, fmt.Errorf("failed to parse time: %w", err)
+	case JSONFormatName:
+		return NewJSONFormat(prm.JSONKeys), JSONFormatName, nil
 	}
 
-	status, err := strconv.Atoi(matches[7])
+	format, err := compileDecoder(prm.LogFormat, "", prm.JSONKeys)
 	if err != nil {
-		return log{}, fmt.Errorf("failed to parse status: %w", err)
-	}
-
-	if http.StatusText(status) == "" {
-		return log{}, NewErrBadStatus("no such status")
+		return nil, "", fmt.Errorf("compile log format %q: %w", prm.LogFormat, err)
 	}
 
-	bodyBytesSent, err := strconv.Atoi(matches[8])
-	if err != nil {
-		return log{}, fmt.Errorf("failed to parse bodyBytesSend: %w", err)
-	}
-
-	return log{
-		RemoteAddress: matches[1],
-		RemoteUser:    matches[2],
-		TimeLocal:     parsedTime,
-		Method:        matches[4],
-		URL:           matches[5],
-		HTTPVersion:   matches[6],
-		Status:        status,
-		BodyBytesSend: bodyBytesSent,
-		Referer:       matches[9],
-		UserAgent:     matches[10],
-	}, nil
+	return format, "custom", nil
 }
 
 func fanIn[T any](
@@ -272,19 +418,43 @@ func fanIn[T any](
 	return out
 }
 
-func (p *Parser) read(ctx context.Context, eg *errgroup.Group, reader io.ReadCloser) <-chan line {
+func (p *Parser) read(ctx context.Context, eg *errgroup.Group, src *source) <-chan line {
 	lines := make(chan line)
 
-	lineNumber := 1
-	scan := bufio.NewScanner(reader)
+	lineNumber := src.lines + 1
+	consumed := int64(0)
+
+	splitter := &lineSplitter{}
+
+	scan := bufio.NewScanner(src.reader)
+	scan.Buffer(make([]byte, 0, lineBufferSize), maxLineLength)
+	scan.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && src.holdPartial && bytes.IndexByte(data, '\n') == -1 {
+			return 0, nil, nil
+		}
+
+		advance, token, err := splitter.split(data, atEOF)
+		consumed += int64(advance)
+
+		return advance, token, err
+	})
 
 	eg.Go(func() error {
 		defer close(lines)
 
+		defer func() {
+			src.offset += consumed
+			src.lines = lineNumber - 1
+		}()
+
 		for scan.Scan() {
-			text := scan.Text()
+			curLine := newLine(scan.Text(), lineNumber, src.name, src.format)
+			if splitter.tooLong {
+				curLine.err = NewErrLineTooLong(fmt.Sprintf("line is longer than %d bytes", maxLineLength))
+			}
+
 			select {
-			case lines <- newLine(text, lineNumber):
+			case lines <- curLine:
 
 			case <-ctx.Done():
 				return nil
@@ -293,31 +463,33 @@ func (p *Parser) read(ctx context.Context, eg *errgroup.Group, reader io.ReadClo
 			lineNumber++
 		}
 
+		if err := scan.Err(); err != nil {
+			return fmt.Errorf("read %q: %w", src.name, err)
+		}
+
 		return nil
 	})
 
 	return lines
 }
 
-func (p *Parser) parseFilesFanOut(
-	ctx context.Context,
-	eg *errgroup.Group,
-	files []*os.File,
-) []<-chan line {
-	chs := make([]<-chan line, len(files))
-
-	for i, f := range files {
-		chs[i] = p.read(ctx, eg, f)
-		files[i] = f
+func (p *Parser) skipMalformed(curLine *line, err error, policy *ErrorPolicy, parseData *data) error {
+	if policy.Mode == FailFast {
+		return fmt.Errorf("convert line #%d to log entry: %w", curLine.number, err)
 	}
 
-	return chs
+	malformed := parseData.addMalformed(curLine.source, errorType(err), curLine.number)
+
+	return policy.checkCount(malformed)
 }
 
 func (p *Parser) convertLine(
 	ctx context.Context,
 	eg *errgroup.Group,
+	policy *ErrorPolicy,
+	loc *time.Location,
 	lines <-chan line,
+	parseData *data,
 ) <-chan log {
 	logs := make(chan log)
 
@@ -325,9 +497,19 @@ func (p *Parser) convertLine(
 		defer close(logs)
 
 		for curLine := range lines {
-			logEntry, err := p.lineToLog(curLine.text)
+			parseData.addLines(1)
+
+			logEntry, err := curLine.decode()
 			if err != nil {
-				return fmt.Errorf("convert line #%d to log entry: %w", curLine.number, err)
+				if err := p.skipMalformed(&curLine, err, policy, parseData); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if loc != nil {
+				logEntry.TimeLocal = logEntry.TimeLocal.In(loc)
 			}
 
 			select {
@@ -349,12 +531,15 @@ const convertGoroutines = 2
 func (p *Parser) convertLineFanOut(
 	ctx context.Context,
 	eg *errgroup.Group,
+	policy *ErrorPolicy,
+	loc *time.Location,
 	lines <-chan line,
+	parseData *data,
 ) []<-chan log {
 	chs := make([]<-chan log, convertGoroutines)
 
 	for i := range convertGoroutines {
-		chs[i] = p.convertLine(ctx, eg, lines)
+		chs[i] = p.convertLine(ctx, eg, policy, loc, lines, parseData)
 	}
 
 	return chs
@@ -372,8 +557,7 @@ func (p *Parser) filterTime(
 		defer close(finalChan)
 
 		for lg := range filterChan {
-			if from != nil && from.After(lg.TimeLocal) ||
-				to != nil && to.Before(lg.TimeLocal.Truncate(24*time.Hour)) {
+			if !inTimeRange(lg.TimeLocal, from, to) {
 				continue
 			}
 
@@ -408,67 +592,10 @@ func (p *Parser) filterTimeFanOut(
 	return chs
 }
 
-func matchLogByField(logEntry *log, filed, pattern string) (bool, error) {
-	fieldValue := reflect.ValueOf(*logEntry).FieldByName(filed)
-
-	if !fieldValue.IsValid() {
-		return true, nil
-	}
-
-	var value string
-
-	switch fieldValue.Kind() {
-	case reflect.String:
-		value = fieldValue.String()
-
-	case reflect.Int:
-		value = fmt.Sprintf("%d", fieldValue.Int())
-
-	case reflect.Struct:
-		if fieldValue.Type() == reflect.TypeOf(time.Time{}) {
-			tm := fieldValue.Interface().(time.Time)
-			value = tm.Format(timeLayout)
-		}
-
-	case reflect.Invalid,
-		reflect.Bool,
-		reflect.Int8,
-		reflect.Int16,
-		reflect.Int32,
-		reflect.Int64,
-		reflect.Uint,
-		reflect.Uint8,
-		reflect.Uint16,
-		reflect.Uint32,
-		reflect.Uint64,
-		reflect.Uintptr,
-		reflect.Float32,
-		reflect.Float64,
-		reflect.Complex64,
-		reflect.Complex128,
-		reflect.Array,
-		reflect.Chan,
-		reflect.Func,
-		reflect.Interface,
-		reflect.Map,
-		reflect.Pointer,
-		reflect.Slice,
-		reflect.UnsafePointer:
-		return false, nil
-	}
-
-	matched, err := regexp.MatchString(pattern, value)
-	if err != nil {
-		return false, fmt.Errorf("error matching regex: %w", err)
-	}
-
-	return matched, nil
-}
-
 func (p *Parser) filterField(
 	ctx context.Context,
 	eg *errgroup.Group,
-	field, value string,
+	filter *Filter,
 	filterChan <-chan log,
 ) <-chan log {
 	finalChan := make(chan log)
@@ -477,12 +604,7 @@ func (p *Parser) filterField(
 		defer close(finalChan)
 
 		for lg := range filterChan {
-			match, err := matchLogByField(&lg, field, value)
-			if err != nil {
-				return fmt.Errorf("matching log by field=%q with value = %q: %w", field, value, err)
-			}
-
-			if match {
+			if filter.match(&lg) {
 				select {
 				case finalChan <- lg:
 
@@ -503,13 +625,13 @@ const filterFieldGoroutines = 2
 func (p *Parser) filterFieldFanOut(
 	ctx context.Context,
 	eg *errgroup.Group,
-	field, value string,
+	filter *Filter,
 	filterChan <-chan log,
 ) []<-chan log {
 	chs := make([]<-chan log, filterFieldGoroutines)
 
 	for i := range filterTimeGoroutines {
-		chs[i] = p.filterField(ctx, eg, field, value, filterChan)
+		chs[i] = p.filterField(ctx, eg, filter, filterChan)
 	}
 
 	return chs
@@ -529,7 +651,9 @@ func (p *Parser) collect(
 					return nil
 				}
 
-				parseData.processLog(&lg)
+				if err := parseData.processLog(&lg); err != nil {
+					return err
+				}
 
 			case <-ctx.Done():
 				return nil
@@ -551,141 +675,90 @@ func (p *Parser) collectFanOut(
 	}
 }
 
-func (p *Parser) Parse(prm Params) (*domain.FileInfo, error) {
-	var lines <-chan line
-
-	parseData := newData()
-	eg, ctx := errgroup.WithContext(context.Background())
-
-	if pathURL, err := parseURL(prm.Path); err == nil {
-		parseData.paths = []string{pathURL.String()}
-
-		resp, err := http.Get(pathURL.String())
-		if err != nil {
-			return nil, fmt.Errorf("get file from url: %w", err)
-		}
-
-		defer closeResource(resp.Body)
-
-		lines = p.read(ctx, eg, resp.Body)
-	} else {
-		slog.Debug(fmt.Sprintf("parse %q as url: %s", prm.Path, err))
-
-		paths, err := filepath.Glob(prm.Path)
-		if err != nil {
-			return nil, fmt.Errorf("find files for pattern %q: %w", prm.Path, err)
-		}
-
-		parseData.paths = paths
-
-		files, err := getFiles(paths)
-		if err != nil {
-			return nil, fmt.Errorf("getFiles(%q): %w", prm.Path, err)
-		}
-
-		defer closeFiles(files)
-
-		lines = fanIn(ctx, eg, p.parseFilesFanOut(ctx, eg, files)...)
-	}
-
-	filterTimeChan := fanIn(ctx, eg, p.convertLineFanOut(ctx, eg, lines)...)
+func (p *Parser) process(
+	ctx context.Context,
+	eg *errgroup.Group,
+	prm *Params,
+	filter *Filter,
+	lines <-chan line,
+	parseData *data,
+) {
+	filterTimeChan := fanIn(ctx, eg, p.convertLineFanOut(ctx, eg, &prm.ErrorPolicy, prm.TimeZone, lines, parseData)...)
 	filterFieldChan := fanIn(
 		ctx,
 		eg,
-		p.filterFieldFanOut(ctx, eg, prm.FilterField, prm.FilterValue, filterTimeChan)...)
+		p.filterFieldFanOut(ctx, eg, filter, filterTimeChan)...)
 	collectChan := fanIn(
 		ctx,
 		eg,
 		p.filterTimeFanOut(ctx, eg, prm.From, prm.To, filterFieldChan)...)
-	p.collectFanOut(ctx, eg, collectChan, &parseData)
+	p.collectFanOut(ctx, eg, collectChan, parseData)
+}
 
-	if err := eg.Wait(); err != nil {
-		return nil, fmt.Errorf("eg.Wait(): %w", err)
+func compileFilters(prm *Params, decoders ...decoder) (*Filter, error) {
+	variables, open := decoderVariables(decoders...)
+
+	filter, err := compileFilterIn(prm.Filter, prm.TimeZone, variables, open)
+	if err != nil {
+		return nil, fmt.Errorf("compile filter: %w", err)
 	}
 
-	fileInfo := dataToFileInfo(&parseData)
+	fieldFilter, err := CompileFieldFilter(prm.FilterField, prm.FilterValue, variables, open)
+	if err != nil {
+		return nil, fmt.Errorf("compile field filter: %w", err)
+	}
 
-	return fileInfo, nil
+	return fieldFilter.and(filter), nil
 }
 
-func (p *Parser) Markdown(info *domain.FileInfo, out io.Writer) {
-	fmt.Fprint(out, "#### General information\n\n")
-	fmt.Fprint(out, "| Метрика | Значение |\n")
-	fmt.Fprint(out, "|:-|-:|\n")
-	fmt.Fprintf(out, "| Files | %s |\n", strings.Join(info.Paths, ", "))
-	fmt.Fprintf(out, "| Number of requests | %d |\n", info.TotalRequests)
-	fmt.Fprintf(out, "| Average response size | %d |\n", info.AvgResponseSize)
-	fmt.Fprintf(out, "| 95th Percentile of response size | %d |\n", info.ResponseSize95p)
-	fmt.Fprintf(out, "| Average requests per day | %d |\n\n", info.AvgResponsePerDay)
+func (p *Parser) Parse(prm Params) (*domain.FileInfo, error) {
+	sources, err := p.openSources(&prm)
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Fprint(out, "#### Requested resources\n\n")
-	fmt.Fprint(out, "| Resource | Count |\n")
-	fmt.Fprint(out, "|:-|-:|\n")
+	defer closeSources(sources)
 
-	for _, url := range info.FrequentURLs {
-		fmt.Fprintf(out, "| `%s` | %d |\n", url.Name, url.Quantity)
+	if err := checkTimeRange(&prm, sourceDecoders(sources)...); err != nil {
+		return nil, err
 	}
 
-	fmt.Fprint(out, "\n#### Response codes\n\n")
-	fmt.Fprint(out, "| Code | Name | Count |\n")
-	fmt.Fprint(out, "|:-|:-:|-:|\n")
-
-	for _, status := range info.FrequentStatuses {
-		fmt.Fprintf(out, "| %d | %s | %d |\n", status.Code, status.Name, status.Quantity)
+	filter, err := compileFilters(&prm, sourceDecoders(sources)...)
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Fprint(out, "\n#### Requesting addresses\n\n")
-	fmt.Fprint(out, "| Address | Count |\n")
-	fmt.Fprint(out, "|:-|-:|\n")
+	parseData := newData(prm.Top)
+	parseData.quantiles = prm.Quantiles
+	parseData.timeZone = prm.timeZone()
+	parseData.location = prm.location()
+	parseData.bucket = prm.Bucket
 
-	for _, address := range info.FrequentAddresses {
-		fmt.Fprintf(out, "| `%s` | %d |\n", address.Name, address.Quantity)
+	st, err := p.resumeSources(&prm, sources, &parseData)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (p *Parser) Adoc(info *domain.FileInfo, out io.Writer) {
-	fmt.Fprint(out, "==== General Information\n\n")
-	fmt.Fprint(out, "[options=\"header\"]\n")
-	fmt.Fprint(out, "|===\n")
-	fmt.Fprint(out, "| Метрика | Значение\n")
+	parseData.addSources(sources)
 
-	fmt.Fprintf(out, "| Files | %s\n", strings.Join(info.Paths, ", "))
-	fmt.Fprintf(out, "| Number of requests | %d\n", info.TotalRequests)
-	fmt.Fprintf(out, "| Average response size | %d\n", info.AvgResponseSize)
-	fmt.Fprintf(out, "| 95th percentile of response size | %d\n", info.ResponseSize95p)
-	fmt.Fprintf(out, "| Average requests per day | %d |\n", info.AvgResponsePerDay)
-	fmt.Fprint(out, "|===\n\n")
+	eg, ctx := errgroup.WithContext(context.Background())
 
-	fmt.Fprint(out, "==== Requested Resources\n\n")
-	fmt.Fprint(out, "[options=\"header\"]\n")
-	fmt.Fprint(out, "|===\n")
-	fmt.Fprint(out, "| Resource | Count\n")
+	lines := fanIn(ctx, eg, p.parseSourcesFanOut(ctx, eg, sources)...)
+	p.process(ctx, eg, &prm, filter, lines, &parseData)
 
-	for _, url := range info.FrequentURLs {
-		fmt.Fprintf(out, "| `%s` | %d\n", url.Name, url.Quantity)
+	if err := eg.Wait(); err != nil {
+		return nil, fmt.Errorf("eg.Wait(): %w", err)
 	}
 
-	fmt.Fprint(out, "|===\n\n")
-
-	fmt.Fprint(out, "==== Response Codes\n\n")
-	fmt.Fprint(out, "[options=\"header\"]\n")
-	fmt.Fprint(out, "|===\n")
-	fmt.Fprint(out, "| Code | Name | Count\n")
-
-	for _, status := range info.FrequentStatuses {
-		fmt.Fprintf(out, "| %d | %s | %d\n", status.Code, status.Name, status.Quantity)
+	if err := prm.ErrorPolicy.checkPercent(parseData.malformedCount, parseData.totalLines); err != nil {
+		return nil, err
 	}
 
-	fmt.Fprint(out, "|===\n\n")
-
-	fmt.Fprint(out, "==== Requesting addresses\n\n")
-	fmt.Fprint(out, "[options=\"header\"]\n")
-	fmt.Fprint(out, "|===\n")
-	fmt.Fprint(out, "| Name | Count\n")
-
-	for _, address := range info.FrequentAddresses {
-		fmt.Fprintf(out, "| %s | %d\n", address.Name, address.Quantity)
+	if err := p.checkpoint(&prm, st, sources, &parseData); err != nil {
+		return nil, err
 	}
 
-	fmt.Fprint(out, "|===\n")
+	fileInfo := dataToFileInfo(&parseData)
+
+	return fileInfo, nil
 }
@@ -1,10 +1,10 @@
 package parser_test
 
 import (
-	"bytes"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -116,7 +116,7 @@ func TestParseFile(t *testing.T) {
 			logParser := parser.New()
 
 			data, err := logParser.Parse(parser.Params{
-				Path: fileName,
+				Paths: []string{fileName},
 			})
 			require.NoError(t, err, "file must be parsed")
 
@@ -203,7 +203,7 @@ func TestParseMultipleFiles(t *testing.T) {
 			logParser := parser.New()
 
 			data, err := logParser.Parse(parser.Params{
-				Path: fileName,
+				Paths: []string{fileName},
 			})
 			require.NoError(t, err, "file must be parsed")
 
@@ -272,7 +272,7 @@ func TestParseFileWithTimeFilter(t *testing.T) {
 				`HTTP/1.1" 200 1844 "-" "Mozilla/5.0 (Macintosh; PPC Mac OS X 10_7_7 rv:6.0; en-US) AppleWebKit/533.23.2 ` +
 				`(KHTML, like Gecko) Version/4.2 Safari/533.23.2"`,
 			from:              nil,
-			to:                getTime(t, "24/Oct/2024"),
+			to:                getTime(t, "25/Oct/2024"),
 			totalRequests:     2,
 			avgResponseSize:   2012,
 			responseSize95p:   2739,
@@ -315,7 +315,7 @@ func TestParseFileWithTimeFilter(t *testing.T) {
 				`"Mozilla/5.0 (X11; Linux i686) AppleWebKit/5330 ` +
 				`(KHTML, like Gecko) Chrome/37.0.829.0 Mobile Safari/5330"`,
 			from:              getTime(t, "23/Oct/2024"),
-			to:                getTime(t, "25/Oct/2024"),
+			to:                getTime(t, "26/Oct/2024"),
 			totalRequests:     3,
 			avgResponseSize:   1227,
 			responseSize95p:   2668,
@@ -340,9 +340,9 @@ func TestParseFileWithTimeFilter(t *testing.T) {
 			logParser := parser.New()
 
 			data, err := logParser.Parse(parser.Params{
-				Path: fileName,
-				From: tc.from,
-				To:   tc.to,
+				Paths: []string{fileName},
+				From:  tc.from,
+				To:    tc.to,
 			})
 			require.NoError(t, err, "file must be parsed")
 
@@ -572,7 +572,7 @@ func TestParseFileWithFieldFilter(t *testing.T) {
 			logParser := parser.New()
 
 			data, err := logParser.Parse(parser.Params{
-				Path:        fileName,
+				Paths:       []string{fileName},
 				FilterField: tc.field,
 				FilterValue: tc.value,
 			})
@@ -609,7 +609,7 @@ func TestParseFileContentError(t *testing.T) {
 		{
 			name: "no such status",
 			content: `219.251.118.203 - - [22/Oct/2024:09:48:45 +0000] "GET /methodology/systemic_Phased-user-facing.php ` +
-				`HTTP/1.1" 199 1040 "-" ` +
+				`HTTP/1.1" 600 1040 "-" ` +
 				`"Mozilla/5.0 (iPhone; CPU iPhone OS 8_0_2 like Mac OS X; en-US) ` +
 				`AppleWebKit/532.6.6 (KHTML, like Gecko) Version/4.0.5 ` +
 				`Mobile/8B117 Safari/6532.6.6"`,
@@ -624,13 +624,337 @@ func TestParseFileContentError(t *testing.T) {
 			logParser := parser.New()
 
 			_, err := logParser.Parse(parser.Params{
-				Path: fileName,
+				Paths: []string{fileName},
 			})
 			require.Error(t, err, "bad content")
 		})
 	}
 }
 
+func TestParseFileWithLogFormat(t *testing.T) {
+	tt := []struct {
+		name          string
+		logFormat     string
+		content       string
+		filterField   string
+		filterValue   string
+		totalRequests int
+		frequentURLs  []domain.URL
+	}{
+		{
+			name: "custom variables",
+			logFormat: `'$remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent ` +
+				`"$http_referer" "$http_user_agent" $request_time $upstream_addr'`,
+			content: `10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /index.html HTTP/1.1" 200 512 "-" "curl/8.0" ` +
+				`0.004 127.0.0.1:8080` + "\n" +
+				`10.0.0.2 - - [22/Oct/2024:09:48:46 +0000] "GET /api HTTP/1.1" 502 0 "-" "curl/8.0" ` +
+				`1.250 127.0.0.1:8081`,
+			filterField:   "upstream_addr",
+			filterValue:   `:8081$`,
+			totalRequests: 1,
+			frequentURLs:  []domain.URL{domain.NewURL("/api", 1)},
+		},
+		{
+			name:      "split request",
+			logFormat: `$time_iso8601 $remote_addr $request_method $request_uri $status $body_bytes_sent`,
+			content: `2024-10-22T09:48:45+00:00 10.0.0.1 GET /a 200 10` + "\n" +
+				`2024-10-22T09:48:46+00:00 10.0.0.1 POST /a 201 20`,
+			totalRequests: 2,
+			frequentURLs:  []domain.URL{domain.NewURL("/a", 2)},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fileName := createTestFiles(t, tc.content)
+			defer deleteTestFiles(t, getRoot(fileName))
+
+			logParser := parser.New()
+
+			data, err := logParser.Parse(parser.Params{
+				Paths:       []string{fileName},
+				LogFormat:   tc.logFormat,
+				FilterField: tc.filterField,
+				FilterValue: tc.filterValue,
+			})
+			require.NoError(t, err, "file must be parsed")
+
+			assert.Equal(t, tc.totalRequests, data.TotalRequests)
+			assert.Equal(t, tc.frequentURLs, data.FrequentURLs)
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	tt := []struct {
+		name          string
+		logFormat     string
+		jsonKeys      map[string]string
+		content       string
+		filterField   string
+		filterValue   string
+		totalRequests int
+		frequentURLs  []domain.URL
+	}{
+		{
+			name:      "variable names as keys",
+			logFormat: "json",
+			content: `{"remote_addr":"10.0.0.1","time_local":"22/Oct/2024:09:48:45 +0000",` +
+				`"request":"GET /a HTTP/1.1","status":200,"body_bytes_sent":"10","request_id":"abc"}` + "\n" +
+				`{"remote_addr":"10.0.0.2","time_local":"22/Oct/2024:09:48:46 +0000",` +
+				`"request":"GET /b HTTP/1.1","status":404,"body_bytes_sent":"0","request_id":"def"}`,
+			filterField:   "request_id",
+			filterValue:   "^def$",
+			totalRequests: 1,
+			frequentURLs:  []domain.URL{domain.NewURL("/b", 1)},
+		},
+		{
+			name:      "key mapping",
+			logFormat: "json",
+			jsonKeys:  map[string]string{"ts": "time_iso8601", "ip": "remote_addr", "uri": "request_uri"},
+			content: `{"ts":"2024-10-22T09:48:45+00:00","ip":"10.0.0.1","uri":"/a","status":"200","body_bytes_sent":"10"}` +
+				"\n" + `{"ts":"2024-10-22T09:48:46+00:00","ip":"10.0.0.1","uri":"/a","status":"200","body_bytes_sent":"30"}`,
+			totalRequests: 2,
+			frequentURLs:  []domain.URL{domain.NewURL("/a", 2)},
+		},
+		{
+			name:      "log_format template",
+			logFormat: `'{"ts":"$time_iso8601","ip":"$remote_addr","req":"$request","code":$status,"size":$body_bytes_sent}'`,
+			content: `{"ts":"2024-10-22T09:48:45+00:00","ip":"10.0.0.1","req":"GET /a HTTP/1.1","code":200,"size":10}` +
+				"\n" + `{"ts":"2024-10-22T09:48:46+00:00","ip":"10.0.0.2","req":"POST /b HTTP/1.1","code":201,"size":20}`,
+			filterField:   "Method",
+			filterValue:   "POST",
+			totalRequests: 1,
+			frequentURLs:  []domain.URL{domain.NewURL("/b", 1)},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fileName := createTestFiles(t, tc.content)
+			defer deleteTestFiles(t, getRoot(fileName))
+
+			logParser := parser.New()
+
+			data, err := logParser.Parse(parser.Params{
+				Paths:       []string{fileName},
+				LogFormat:   tc.logFormat,
+				JSONKeys:    tc.jsonKeys,
+				FilterField: tc.filterField,
+				FilterValue: tc.filterValue,
+			})
+			require.NoError(t, err, "file must be parsed")
+
+			assert.Equal(t, tc.totalRequests, data.TotalRequests)
+			assert.Equal(t, tc.frequentURLs, data.FrequentURLs)
+		})
+	}
+}
+
+func TestParseJSONError(t *testing.T) {
+	fileName := createTestFiles(t, `{"remote_addr":"10.0.0.1",`)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	logParser := parser.New()
+
+	_, err := logParser.Parse(parser.Params{
+		Paths:     []string{fileName},
+		LogFormat: "json",
+	})
+	require.ErrorAs(t, err, &parser.ErrJSON{})
+}
+
+func TestParseJSONMissingTime(t *testing.T) {
+	content := `{"time_local":"22/Oct/2024:09:48:45 +0000","remote_addr":"10.0.0.1","status":"200"}` + "\n" +
+		`{"remote_addr":"10.0.0.2","status":"200"}` + "\n"
+
+	fileName := createTestFiles(t, content)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	data, err := parser.New().Parse(parser.Params{
+		Paths:       []string{fileName},
+		LogFormat:   "json",
+		ErrorPolicy: parser.ErrorPolicy{Mode: parser.Skip},
+	})
+	require.NoError(t, err, "line without time must be skipped")
+
+	assert.Equal(t, 1, data.TotalRequests)
+	assert.Equal(t, []domain.Malformed{
+		domain.NewMalformed(data.Paths[0], "missing_time", 1, []int{2}),
+	}, data.Malformed)
+	assert.Equal(t, "2024-10-22", data.RequestsPerDay[0].Day)
+}
+
+func TestParseDetectFormat(t *testing.T) {
+	content := []string{
+		`10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0"`,
+		`10.0.0.2 - - [22/Oct/2024:09:48:45 +0000] "GET /b HTTP/1.1" 200 200`,
+		`{"remote_addr":"10.0.0.3","time_local":"22/Oct/2024:09:48:45 +0000",` +
+			`"request":"GET /c HTTP/1.1","status":"200","body_bytes_sent":"300"}`,
+		`example.com:443 10.0.0.4 - - [22/Oct/2024:09:48:45 +0000] "GET /d HTTP/1.1" 200 400 "-" "curl/8.0"`,
+		`10.0.0.5 ident frank [22/Oct/2024:09:48:45 +0000] "GET /e HTTP/1.1" 304 - "-" "curl/8.0"`,
+	}
+
+	fileName := createTestFiles(t, content...)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	logParser := parser.New()
+
+	data, err := logParser.Parse(parser.Params{
+		Paths: []string{fileName},
+	})
+	require.NoError(t, err, "files must be parsed")
+
+	formats := make([]string, len(data.Formats))
+	for i, format := range data.Formats {
+		formats[i] = format.Format
+	}
+
+	assert.Equal(t, []string{"combined", "common", "json", "vhost_combined", "apache"}, formats)
+	assert.Equal(t, 5, data.TotalRequests)
+	assert.Equal(t, 200, data.AvgResponseSize)
+}
+
+func TestParseSkipMalformed(t *testing.T) {
+	content := `10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0"` + "\n" +
+		`truncated line` + "\n" +
+		`10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0"` + "\n" +
+		`10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 600 100 "-" "curl/8.0"` + "\n" +
+		`10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0"` + "\n" +
+		`another truncated line` + "\n" +
+		`10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0"` + "\n" +
+		`10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0"`
+
+	tt := []struct {
+		name          string
+		policy        parser.ErrorPolicy
+		expectedError bool
+	}{
+		{
+			name:   "skip",
+			policy: parser.ErrorPolicy{Mode: parser.Skip},
+		},
+		{
+			name:   "skip up to limit",
+			policy: parser.ErrorPolicy{Mode: parser.Skip, MaxErrors: 3},
+		},
+		{
+			name:          "too many errors",
+			policy:        parser.ErrorPolicy{Mode: parser.Skip, MaxErrors: 2},
+			expectedError: true,
+		},
+		{
+			name:   "skip up to percent",
+			policy: parser.ErrorPolicy{Mode: parser.Skip, MaxPercent: 40},
+		},
+		{
+			name:          "too big percent",
+			policy:        parser.ErrorPolicy{Mode: parser.Skip, MaxPercent: 30},
+			expectedError: true,
+		},
+		{
+			name:          "fail fast",
+			policy:        parser.ErrorPolicy{Mode: parser.FailFast},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fileName := createTestFiles(t, content)
+			defer deleteTestFiles(t, getRoot(fileName))
+
+			logParser := parser.New()
+
+			data, err := logParser.Parse(parser.Params{
+				Paths:       []string{fileName},
+				ErrorPolicy: tc.policy,
+			})
+			if tc.expectedError {
+				require.Error(t, err, "too many malformed lines")
+				return
+			}
+
+			require.NoError(t, err, "malformed lines must be skipped")
+
+			assert.Equal(t, 5, data.TotalRequests)
+			assert.Equal(t, 8, data.TotalLines)
+			assert.Equal(t, []domain.Malformed{
+				domain.NewMalformed(data.Paths[0], "regexp", 2, []int{2, 6}),
+				domain.NewMalformed(data.Paths[0], "status", 1, []int{4}),
+			}, data.Malformed)
+		})
+	}
+}
+
+func TestParseLongLines(t *testing.T) {
+	good := `10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0"`
+
+	tt := []struct {
+		name    string
+		length  int
+		errType string
+	}{
+		{
+			name:    "longer than the scanner buffer",
+			length:  100 * 1024,
+			errType: "regexp",
+		},
+		{
+			name:    "longer than the line limit",
+			length:  2 * 1024 * 1024,
+			errType: "too_long",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fileName := createTestFiles(t, good+"\n"+strings.Repeat("x", tc.length)+"\n"+good+"\n")
+			defer deleteTestFiles(t, getRoot(fileName))
+
+			data, err := parser.New().Parse(parser.Params{
+				Paths:       []string{fileName},
+				ErrorPolicy: parser.ErrorPolicy{Mode: parser.Skip},
+			})
+			require.NoError(t, err, "long line must be skipped")
+
+			assert.Equal(t, 2, data.TotalRequests)
+			assert.Equal(t, 3, data.TotalLines)
+			assert.Equal(t, []domain.Malformed{
+				domain.NewMalformed(data.Paths[0], tc.errType, 1, []int{2}),
+			}, data.Malformed)
+		})
+	}
+}
+
+func TestParseNginxStatuses(t *testing.T) {
+	content := `10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 499 0 "-" "curl/8.0"` + "\n" +
+		`10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 499 0 "-" "curl/8.0"` + "\n" +
+		`10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 444 0 "-" "curl/8.0"` + "\n" +
+		`10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 299 0 "-" "curl/8.0"`
+
+	fileName := createTestFiles(t, content)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	logParser := parser.New()
+
+	data, err := logParser.Parse(parser.Params{
+		Paths: []string{fileName},
+	})
+	require.NoError(t, err, "nginx statuses must be accepted")
+
+	assert.Equal(t, []domain.Status{
+		{Code: 499, Name: "Client Closed Request", Quantity: 2},
+		{Code: 299, Name: "Unknown Success", Quantity: 1},
+		{Code: 444, Name: "No Response", Quantity: 1},
+	}, data.FrequentStatuses)
+}
+
+func TestCompileFormatError(t *testing.T) {
+	_, err := parser.CompileFormat("no variables here")
+	require.ErrorAs(t, err, &parser.ErrLogFormat{})
+}
+
 func TestParseFileExistenceError(t *testing.T) {
 	tt := []struct {
 		name     string
@@ -647,7 +971,7 @@ func TestParseFileExistenceError(t *testing.T) {
 			logParser := parser.New()
 
 			_, err := logParser.Parse(parser.Params{
-				Path: tc.fileName,
+				Paths: []string{tc.fileName},
 			})
 			require.Error(t, err, "bad content")
 		})
@@ -709,7 +1033,7 @@ func TestParseURL(t *testing.T) {
 			logParser := parser.New()
 
 			data, err := logParser.Parse(parser.Params{
-				Path: server.URL,
+				Paths: []string{server.URL},
 			})
 			require.NoError(t, err, "must parse data from server")
 
@@ -724,6 +1048,74 @@ func TestParseURL(t *testing.T) {
 	}
 }
 
+func TestParseMultipleSources(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /url HTTP/1.1" 200 100 "-" "curl/8.0"`)
+		}),
+	)
+	defer server.Close()
+
+	fileName := createTestFiles(t,
+		`10.0.0.2 - - [22/Oct/2024:09:48:45 +0000] "GET /file HTTP/1.1" 200 200 "-" "curl/8.0"`)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	stdin := strings.NewReader(
+		`10.0.0.3 - - [22/Oct/2024:09:48:45 +0000] "GET /stdin HTTP/1.1" 200 300 "-" "curl/8.0"` + "\n" +
+			`10.0.0.3 - - [22/Oct/2024:09:48:45 +0000] "GET /stdin HTTP/1.1" 200 300 "-" "curl/8.0"`)
+
+	logParser := parser.New()
+
+	data, err := logParser.Parse(parser.Params{
+		Paths: []string{server.URL, fileName, "-"},
+		Stdin: stdin,
+	})
+	require.NoError(t, err, "all sources must be parsed")
+
+	require.Len(t, data.Paths, 3)
+	assert.Equal(t, server.URL, data.Paths[0])
+	assert.Equal(t, "stdin", data.Paths[2])
+	assert.Equal(t, 4, data.TotalRequests)
+	assert.Equal(t, []domain.URL{
+		domain.NewURL("/stdin", 2),
+		domain.NewURL("/file", 1),
+		domain.NewURL("/url", 1),
+	}, data.FrequentURLs)
+}
+
+func TestParseDistributions(t *testing.T) {
+	fileName := createTestFiles(t,
+		`10.0.0.1 - - [23/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 200 0 "-" "curl/8.0"
+10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 404 3 "-" "curl/8.0"
+10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 499 9 "-" "curl/8.0"
+10.0.0.1 - - [22/Oct/2024:09:48:45 +0000] "GET /a HTTP/1.1" 502 12 "-" "curl/8.0"`)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	logParser := parser.New()
+
+	data, err := logParser.Parse(parser.Params{
+		Paths: []string{fileName},
+	})
+	require.NoError(t, err, "file must be parsed")
+
+	assert.Equal(t, []domain.DayRequests{
+		{Day: "2024-10-22", Quantity: 3, UniqueAddresses: 1},
+		{Day: "2024-10-23", Quantity: 1, UniqueAddresses: 1},
+	}, data.RequestsPerDay)
+	assert.Equal(t, []domain.StatusClass{
+		domain.NewStatusClass("2xx", 1),
+		domain.NewStatusClass("4xx", 2),
+		domain.NewStatusClass("5xx", 1),
+	}, data.StatusClasses)
+	assert.Equal(t, []domain.SizeBucket{
+		domain.NewSizeBucket(0, 1, 1),
+		domain.NewSizeBucket(1, 2, 0),
+		domain.NewSizeBucket(2, 4, 1),
+		domain.NewSizeBucket(4, 8, 0),
+		domain.NewSizeBucket(8, 16, 2),
+	}, data.SizeHistogram)
+}
+
 func TestParseURLError(t *testing.T) {
 	tt := []struct {
 		name string
@@ -744,370 +1136,9 @@ func TestParseURLError(t *testing.T) {
 			logParser := parser.New()
 
 			_, err := logParser.Parse(parser.Params{
-				Path: tc.url,
+				Paths: []string{tc.url},
 			})
 			require.Error(t, err, "bad url")
 		})
 	}
 }
-
-func TestMarkdown(t *testing.T) {
-	tt := []struct {
-		name     string
-		info     *domain.FileInfo
-		expected string
-	}{
-		{
-			name: "Single file",
-			info: &domain.FileInfo{
-				Paths:             []string{"/var/log/nginx/access.log"},
-				TotalRequests:     100,
-				AvgResponseSize:   512,
-				ResponseSize95p:   800,
-				AvgResponsePerDay: 10,
-				FrequentURLs: []domain.URL{
-					domain.NewURL("/index.html", 50),
-					domain.NewURL("/about.html", 20),
-				},
-				FrequentStatuses: []domain.Status{
-					domain.NewStatus(200, 80),
-					domain.NewStatus(404, 10),
-				},
-				FrequentAddresses: []domain.Address{
-					domain.NewAddress("192.168.1.1", 30),
-					domain.NewAddress("10.0.0.2", 20),
-				},
-			},
-			expected: "#### General information\n\n" +
-				"| Метрика | Значение |\n" +
-				"|:-|-:|\n" +
-				"| Files | /var/log/nginx/access.log |\n" +
-				"| Number of requests | 100 |\n" +
-				"| Average response size | 512 |\n" +
-				"| 95th Percentile of response size | 800 |\n" +
-				"| Average requests per day | 10 |\n\n" +
-				"#### Requested resources\n\n" +
-				"| Resource | Count |\n" +
-				"|:-|-:|\n" +
-				"| `/index.html` | 50 |\n" +
-				"| `/about.html` | 20 |\n\n" +
-				"#### Response codes\n\n" +
-				"| Code | Name | Count |\n" +
-				"|:-|:-:|-:|\n" +
-				"| 200 | OK | 80 |\n" +
-				"| 404 | Not Found | 10 |\n\n" +
-				"#### Requesting addresses\n\n" +
-				"| Address | Count |\n" +
-				"|:-|-:|\n" +
-				"| `192.168.1.1` | 30 |\n" +
-				"| `10.0.0.2` | 20 |\n",
-		},
-		{
-			name: "Multiple files",
-			info: &domain.FileInfo{
-				Paths:             []string{"/var/log/nginx/access.log", "/var/log/nginx/access.log.1"},
-				TotalRequests:     1000,
-				AvgResponseSize:   1024,
-				ResponseSize95p:   1500,
-				AvgResponsePerDay: 100,
-				FrequentURLs: []domain.URL{
-					domain.NewURL("/home", 300),
-					domain.NewURL("/login", 150),
-					domain.NewURL("/dashboard", 100),
-				},
-				FrequentStatuses: []domain.Status{
-					domain.NewStatus(200, 700),
-					domain.NewStatus(403, 50),
-					domain.NewStatus(500, 20),
-				},
-				FrequentAddresses: []domain.Address{
-					domain.NewAddress("172.16.0.1", 200),
-					domain.NewAddress("192.168.1.2", 150),
-					domain.NewAddress("10.0.0.3", 120),
-				},
-			},
-			expected: "#### General information\n\n" +
-				"| Метрика | Значение |\n" +
-				"|:-|-:|\n" +
-				"| Files | /var/log/nginx/access.log, /var/log/nginx/access.log.1 |\n" +
-				"| Number of requests | 1000 |\n" +
-				"| Average response size | 1024 |\n" +
-				"| 95th Percentile of response size | 1500 |\n" +
-				"| Average requests per day | 100 |\n\n" +
-				"#### Requested resources\n\n" +
-				"| Resource | Count |\n" +
-				"|:-|-:|\n" +
-				"| `/home` | 300 |\n" +
-				"| `/login` | 150 |\n" +
-				"| `/dashboard` | 100 |\n\n" +
-				"#### Response codes\n\n" +
-				"| Code | Name | Count |\n" +
-				"|:-|:-:|-:|\n" +
-				"| 200 | OK | 700 |\n" +
-				"| 403 | Forbidden | 50 |\n" +
-				"| 500 | Internal Server Error | 20 |\n\n" +
-				"#### Requesting addresses\n\n" +
-				"| Address | Count |\n" +
-				"|:-|-:|\n" +
-				"| `172.16.0.1` | 200 |\n" +
-				"| `192.168.1.2` | 150 |\n" +
-				"| `10.0.0.3` | 120 |\n",
-		},
-		{
-			name: "URL",
-			info: &domain.FileInfo{
-				Paths:             []string{"https://raw.githubusercontent.com/elastic/examples/master/Common%20Data%20Formats/nginx_logs/nginx_logs"},
-				TotalRequests:     5000,
-				AvgResponseSize:   2048,
-				ResponseSize95p:   3000,
-				AvgResponsePerDay: 500,
-				FrequentURLs: []domain.URL{
-					domain.NewURL("/home", 1000),
-					domain.NewURL("/products", 800),
-					domain.NewURL("/contact", 600),
-				},
-				FrequentStatuses: []domain.Status{
-					domain.NewStatus(200, 4000),
-					domain.NewStatus(404, 400),
-					domain.NewStatus(503, 50),
-				},
-				FrequentAddresses: []domain.Address{
-					domain.NewAddress("192.168.0.10", 500),
-					domain.NewAddress("192.168.0.20", 450),
-					domain.NewAddress("192.168.0.30", 300),
-				},
-			},
-			expected: "#### General information\n\n" +
-				"| Метрика | Значение |\n" +
-				"|:-|-:|\n" +
-				"| Files | https://raw.githubusercontent.com/elastic/examples/master/Common%20Data%20Formats/nginx_logs/nginx_logs |\n" +
-				"| Number of requests | 5000 |\n" +
-				"| Average response size | 2048 |\n" +
-				"| 95th Percentile of response size | 3000 |\n" +
-				"| Average requests per day | 500 |\n\n" +
-				"#### Requested resources\n\n" +
-				"| Resource | Count |\n" +
-				"|:-|-:|\n" +
-				"| `/home` | 1000 |\n" +
-				"| `/products` | 800 |\n" +
-				"| `/contact` | 600 |\n\n" +
-				"#### Response codes\n\n" +
-				"| Code | Name | Count |\n" +
-				"|:-|:-:|-:|\n" +
-				"| 200 | OK | 4000 |\n" +
-				"| 404 | Not Found | 400 |\n" +
-				"| 503 | Service Unavailable | 50 |\n\n" +
-				"#### Requesting addresses\n\n" +
-				"| Address | Count |\n" +
-				"|:-|-:|\n" +
-				"| `192.168.0.10` | 500 |\n" +
-				"| `192.168.0.20` | 450 |\n" +
-				"| `192.168.0.30` | 300 |\n",
-		},
-	}
-
-	logParser := parser.New()
-
-	for _, tc := range tt {
-		t.Run(tc.name, func(t *testing.T) {
-			buf := &bytes.Buffer{}
-			logParser.Markdown(tc.info, buf)
-
-			assert.Equal(t, tc.expected, buf.String())
-		})
-	}
-}
-
-func TestAdoc(t *testing.T) {
-	tt := []struct {
-		name     string
-		info     *domain.FileInfo
-		expected string
-	}{
-		{
-			name: "Single file",
-			info: &domain.FileInfo{
-				Paths:             []string{"/var/log/nginx/access.log"},
-				TotalRequests:     100,
-				AvgResponseSize:   512,
-				ResponseSize95p:   800,
-				AvgResponsePerDay: 10,
-				FrequentURLs: []domain.URL{
-					domain.NewURL("/index.html", 50),
-					domain.NewURL("/about.html", 20),
-				},
-				FrequentStatuses: []domain.Status{
-					domain.NewStatus(200, 80),
-					domain.NewStatus(404, 10),
-				},
-				FrequentAddresses: []domain.Address{
-					domain.NewAddress("192.168.1.1", 30),
-					domain.NewAddress("10.0.0.2", 20),
-				},
-			},
-			expected: "==== General Information\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Метрика | Значение\n" +
-				"| Files | /var/log/nginx/access.log\n" +
-				"| Number of requests | 100\n" +
-				"| Average response size | 512\n" +
-				"| 95th percentile of response size | 800\n" +
-				"| Average requests per day | 10 |\n" +
-				"|===\n\n" +
-
-				"==== Requested Resources\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Resource | Count\n" +
-				"| `/index.html` | 50\n" +
-				"| `/about.html` | 20\n" +
-				"|===\n\n" +
-
-				"==== Response Codes\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Code | Name | Count\n" +
-				"| 200 | OK | 80\n" +
-				"| 404 | Not Found | 10\n" +
-				"|===\n\n" +
-
-				"==== Requesting addresses\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Name | Count\n" +
-				"| 192.168.1.1 | 30\n" +
-				"| 10.0.0.2 | 20\n" +
-				"|===\n",
-		},
-		{
-			name: "Multiple files",
-			info: &domain.FileInfo{
-				Paths:             []string{"/var/log/nginx/access.log", "/var/log/nginx/access.log.1"},
-				TotalRequests:     1000,
-				AvgResponseSize:   1024,
-				ResponseSize95p:   1500,
-				AvgResponsePerDay: 100,
-				FrequentURLs: []domain.URL{
-					domain.NewURL("/home", 300),
-					domain.NewURL("/login", 150),
-					domain.NewURL("/dashboard", 100),
-				},
-				FrequentStatuses: []domain.Status{
-					domain.NewStatus(200, 700),
-					domain.NewStatus(403, 50),
-					domain.NewStatus(500, 20),
-				},
-				FrequentAddresses: []domain.Address{
-					domain.NewAddress("172.16.0.1", 200),
-					domain.NewAddress("192.168.1.2", 150),
-					domain.NewAddress("10.0.0.3", 120),
-				},
-			},
-			expected: "==== General Information\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Метрика | Значение\n" +
-				"| Files | /var/log/nginx/access.log, /var/log/nginx/access.log.1\n" +
-				"| Number of requests | 1000\n" +
-				"| Average response size | 1024\n" +
-				"| 95th percentile of response size | 1500\n" +
-				"| Average requests per day | 100 |\n" +
-				"|===\n\n" +
-				"==== Requested Resources\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Resource | Count\n" +
-				"| `/home` | 300\n" +
-				"| `/login` | 150\n" +
-				"| `/dashboard` | 100\n" +
-				"|===\n\n" +
-				"==== Response Codes\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Code | Name | Count\n" +
-				"| 200 | OK | 700\n" +
-				"| 403 | Forbidden | 50\n" +
-				"| 500 | Internal Server Error | 20\n" +
-				"|===\n\n" +
-				"==== Requesting addresses\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Name | Count\n" +
-				"| 172.16.0.1 | 200\n" +
-				"| 192.168.1.2 | 150\n" +
-				"| 10.0.0.3 | 120\n" +
-				"|===\n",
-		},
-		{
-			name: "URL",
-			info: &domain.FileInfo{
-				Paths:             []string{"https://raw.githubusercontent.com/elastic/examples/master/Common%20Data%20Formats/nginx_logs/nginx_logs"},
-				TotalRequests:     5000,
-				AvgResponseSize:   2048,
-				ResponseSize95p:   3000,
-				AvgResponsePerDay: 500,
-				FrequentURLs: []domain.URL{
-					domain.NewURL("/home", 1000),
-					domain.NewURL("/products", 800),
-					domain.NewURL("/contact", 600),
-				},
-				FrequentStatuses: []domain.Status{
-					domain.NewStatus(200, 4000),
-					domain.NewStatus(404, 400),
-					domain.NewStatus(503, 50),
-				},
-				FrequentAddresses: []domain.Address{
-					domain.NewAddress("192.168.0.10", 500),
-					domain.NewAddress("192.168.0.20", 450),
-					domain.NewAddress("192.168.0.30", 300),
-				},
-			},
-			expected: "==== General Information\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Метрика | Значение\n" +
-				"| Files | https://raw.githubusercontent.com/elastic/examples/master/Common%20Data%20Formats/nginx_logs/nginx_logs\n" +
-				"| Number of requests | 5000\n" +
-				"| Average response size | 2048\n" +
-				"| 95th percentile of response size | 3000\n" +
-				"| Average requests per day | 500 |\n" +
-				"|===\n\n" +
-				"==== Requested Resources\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Resource | Count\n" +
-				"| `/home` | 1000\n" +
-				"| `/products` | 800\n" +
-				"| `/contact` | 600\n" +
-				"|===\n\n" +
-				"==== Response Codes\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Code | Name | Count\n" +
-				"| 200 | OK | 4000\n" +
-				"| 404 | Not Found | 400\n" +
-				"| 503 | Service Unavailable | 50\n" +
-				"|===\n\n" +
-				"==== Requesting addresses\n\n" +
-				"[options=\"header\"]\n" +
-				"|===\n" +
-				"| Name | Count\n" +
-				"| 192.168.0.10 | 500\n" +
-				"| 192.168.0.20 | 450\n" +
-				"| 192.168.0.30 | 300\n" +
-				"|===\n",
-		},
-	}
-
-	logParser := parser.New()
-
-	for _, tc := range tt {
-		t.Run(tc.name, func(t *testing.T) {
-			buf := &bytes.Buffer{}
-			logParser.Adoc(tc.info, buf)
-
-			assert.Equal(t, tc.expected, buf.String())
-		})
-	}
-}
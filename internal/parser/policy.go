@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+)
+
+type ErrorMode int
+
+const (
+	FailFast ErrorMode = iota
+	Skip
+)
+
+func ParseErrorMode(mode string) (ErrorMode, error) {
+	switch mode {
+	case "", "fail", "fail-fast":
+		return FailFast, nil
+
+	case "skip":
+		return Skip, nil
+	}
+
+	return FailFast, NewErrPolicy(fmt.Sprintf("unknown error mode %q", mode))
+}
+
+type ErrorPolicy struct {
+	Mode       ErrorMode
+	MaxErrors  int
+	MaxPercent float64
+}
+
+const malformedExamples = 3
+
+const (
+	errTypeRegexp = "regexp"
+	errTypeStatus = "status"
+	errTypeTime   = "time"
+	errTypeJSON   = "json"
+	errTypeNumber = "number"
+	errTypeOther  = "other"
+
+	errTypeMissingTime = "missing_time"
+	errTypeTooLong     = "too_long"
+)
+
+func errorType(err error) string {
+	var (
+		errRegexp ErrRegexp
+		errStatus ErrBadStatus
+		errJSON   ErrJSON
+		errNoTime ErrMissingTime
+		errLong   ErrLineTooLong
+		errTime   *time.ParseError
+		errNumber *strconv.NumError
+	)
+
+	switch {
+	case errors.As(err, &errRegexp):
+		return errTypeRegexp
+
+	case errors.As(err, &errStatus):
+		return errTypeStatus
+
+	case errors.As(err, &errTime):
+		return errTypeTime
+
+	case errors.As(err, &errJSON):
+		return errTypeJSON
+
+	case errors.As(err, &errNoTime):
+		return errTypeMissingTime
+
+	case errors.As(err, &errLong):
+		return errTypeTooLong
+
+	case errors.As(err, &errNumber):
+		return errTypeNumber
+	}
+
+	return errTypeOther
+}
+
+type malformedKey struct {
+	path    string
+	errType string
+}
+
+type malformedLines struct {
+	count    int
+	examples []int
+}
+
+func (m *malformedLines) add(lineNumber int) {
+	m.count++
+
+	pos := sort.SearchInts(m.examples, lineNumber)
+	if pos >= malformedExamples {
+		return
+	}
+
+	m.examples = append(m.examples, 0)
+	copy(m.examples[pos+1:], m.examples[pos:])
+	m.examples[pos] = lineNumber
+
+	if len(m.examples) > malformedExamples {
+		m.examples = m.examples[:malformedExamples]
+	}
+}
+
+func malformedToDomain(malformed map[malformedKey]*malformedLines) []domain.Malformed {
+	result := make([]domain.Malformed, 0, len(malformed))
+	for key, lines := range malformed {
+		result = append(result, domain.NewMalformed(key.path, key.errType, lines.count, lines.examples))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+
+		return result[i].Type < result[j].Type
+	})
+
+	return result
+}
+
+func (ep *ErrorPolicy) checkCount(malformed int) error {
+	if ep.MaxErrors > 0 && malformed > ep.MaxErrors {
+		return NewErrTooManyMalformed(
+			fmt.Sprintf("%d malformed lines exceed the limit of %d", malformed, ep.MaxErrors),
+		)
+	}
+
+	return nil
+}
+
+func (ep *ErrorPolicy) checkPercent(malformed, total int) error {
+	if ep.MaxPercent <= 0 || total == 0 {
+		return nil
+	}
+
+	if percent := float64(malformed) * 100 / float64(total); percent > ep.MaxPercent {
+		return NewErrTooManyMalformed(
+			fmt.Sprintf("%.2f%% malformed lines exceed the limit of %.2f%%", percent, ep.MaxPercent),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	sketchPrecisionBits = 12
+	sketchExact         = 1 << sketchPrecisionBits
+	sketchHalf          = sketchExact / 2
+)
+
+var DefaultQuantiles = []float64{0.5, 0.75, 0.9, 0.95, 0.99, 0.999}
+
+type sizeSketch struct {
+	Counts map[int]int `json:"counts"`
+	Count  int         `json:"count"`
+	Max    int         `json:"max"`
+}
+
+type sketchBucket struct {
+	index int
+	count int
+}
+
+func newSizeSketch() *sizeSketch {
+	return &sizeSketch{
+		Counts: make(map[int]int),
+	}
+}
+
+func sketchIndex(value int) int {
+	if value < sketchExact {
+		return max(value, 0)
+	}
+
+	shift := bits.Len(uint(value)) - sketchPrecisionBits
+
+	return sketchExact + (shift-1)*sketchHalf + (value >> shift) - sketchHalf
+}
+
+func sketchLowest(index int) int {
+	if index < sketchExact {
+		return index
+	}
+
+	index -= sketchExact
+	shift := index/sketchHalf + 1
+
+	return (index%sketchHalf + sketchHalf) << shift
+}
+
+func (s *sizeSketch) add(value int) {
+	s.Counts[sketchIndex(value)]++
+	s.Count++
+	s.Max = max(s.Max, value)
+}
+
+func (s *sizeSketch) merge(other *sizeSketch) {
+	for index, count := range other.Counts {
+		s.Counts[index] += count
+	}
+
+	s.Count += other.Count
+	s.Max = max(s.Max, other.Max)
+}
+
+func (s *sizeSketch) buckets() []sketchBucket {
+	buckets := make([]sketchBucket, 0, len(s.Counts))
+	for index, count := range s.Counts {
+		buckets = append(buckets, sketchBucket{index: index, count: count})
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].index < buckets[j].index
+	})
+
+	return buckets
+}
+
+func (s *sizeSketch) quantile(q float64) int {
+	return s.quantiles([]float64{q})[0]
+}
+
+func (s *sizeSketch) quantiles(qs []float64) []int {
+	values := make([]int, len(qs))
+	if s.Count == 0 {
+		return values
+	}
+
+	order := make([]int, len(qs))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		return qs[order[i]] < qs[order[j]]
+	})
+
+	buckets := s.buckets()
+	bucket, seen := 0, buckets[0].count
+
+	for _, i := range order {
+		rank := min(int(qs[i]*float64(s.Count)), s.Count-1)
+		if rank == s.Count-1 {
+			values[i] = s.Max
+			continue
+		}
+
+		for seen <= rank {
+			bucket++
+			seen += buckets[bucket].count
+		}
+
+		values[i] = sketchLowest(buckets[bucket].index)
+	}
+
+	return values
+}
@@ -0,0 +1,100 @@
+package parser_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuantiles(t *testing.T) {
+	tt := []struct {
+		name      string
+		sizes     []int
+		quantiles []float64
+	}{
+		{
+			name:      "small sizes are exact",
+			sizes:     []int{0, 1, 5, 17, 100, 2047, 4095, 3, 3, 3},
+			quantiles: []float64{0.1, 0.5, 0.9, 1},
+		},
+		{
+			name: "large sizes",
+			sizes: func() []int {
+				sizes := make([]int, 5000)
+				for i := range sizes {
+					sizes[i] = (i*7919)%1_000_000 + i
+				}
+
+				return sizes
+			}(),
+			quantiles: parser.DefaultQuantiles,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			content := &strings.Builder{}
+			for _, size := range tc.sizes {
+				content.WriteString(logLine("10.0.0.1", "/", size))
+			}
+
+			fileName := createTestFiles(t, content.String())
+			defer deleteTestFiles(t, getRoot(fileName))
+
+			logParser := parser.New()
+
+			data, err := logParser.Parse(parser.Params{
+				Paths:     []string{fileName},
+				Quantiles: tc.quantiles,
+			})
+			require.NoError(t, err, "file must be parsed")
+
+			sorted := append([]int(nil), tc.sizes...)
+			sort.Ints(sorted)
+
+			require.Len(t, data.ResponseSizeQuantiles, len(tc.quantiles))
+			assert.Equal(t, sorted[len(sorted)-1], data.ResponseSizeMax)
+
+			for i, q := range tc.quantiles {
+				exact := sorted[min(int(q*float64(len(sorted))), len(sorted)-1)]
+				actual := data.ResponseSizeQuantiles[i]
+
+				assert.InDelta(t, q, actual.Quantile, 0)
+				assert.LessOrEqual(t, actual.Value, exact, "quantile %v", q)
+
+				if exact < 4096 {
+					assert.Equal(t, exact, actual.Value, "quantile %v", q)
+				} else {
+					assert.LessOrEqual(t, exact-actual.Value, exact/2048, "quantile %v", q)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDefaultQuantiles(t *testing.T) {
+	fileName := createTestFiles(t, logLine("10.0.0.1", "/", 10)+logLine("10.0.0.1", "/", 20))
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	logParser := parser.New()
+
+	data, err := logParser.Parse(parser.Params{
+		Paths: []string{fileName},
+	})
+	require.NoError(t, err, "file must be parsed")
+
+	assert.Equal(t, []domain.Quantile{
+		domain.NewQuantile(0.5, 20),
+		domain.NewQuantile(0.75, 20),
+		domain.NewQuantile(0.9, 20),
+		domain.NewQuantile(0.95, 20),
+		domain.NewQuantile(0.99, 20),
+		domain.NewQuantile(0.999, 20),
+	}, data.ResponseSizeQuantiles)
+	assert.Equal(t, 20, data.ResponseSize95p)
+}
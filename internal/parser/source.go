@@ -0,0 +1,248 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type source struct {
+	name        string
+	reader      io.Reader
+	closer      io.Closer
+	file        *os.File
+	format      decoder
+	formatName  string
+	compression string
+
+	inode           uint64
+	fingerprint     string
+	fingerprintSize int
+	offset          int64
+	lines           int
+	stateIndex      int
+	holdPartial     bool
+}
+
+func newSource(name string, rc io.ReadCloser, format decoder, formatName string) source {
+	f, _ := rc.(*os.File)
+
+	return source{
+		name:       name,
+		reader:     rc,
+		closer:     rc,
+		file:       f,
+		format:     format,
+		formatName: formatName,
+		stateIndex: -1,
+	}
+}
+
+func closeSources(sources []source) {
+	for _, src := range sources {
+		if src.closer != nil {
+			closeResource(src.closer)
+		}
+	}
+}
+
+func getNginxSources(confPath string, jsonKeys map[string]string) ([]source, error) {
+	nginxLogs, err := ReadNginxConf(confPath)
+	if err != nil {
+		return nil, fmt.Errorf("ReadNginxConf(%q): %w", confPath, err)
+	}
+
+	sources := make([]source, 0, len(nginxLogs))
+	opened := make(map[string]bool)
+
+	for _, nginxLog := range nginxLogs {
+		format, err := compileDecoder(nginxLog.Format, nginxLog.Escape, jsonKeys)
+		if err != nil {
+			closeSources(sources)
+			return nil, fmt.Errorf("compile log format %q: %w", nginxLog.FormatName, err)
+		}
+
+		paths, err := filepath.Glob(nginxLog.Path)
+		if err != nil {
+			closeSources(sources)
+			return nil, fmt.Errorf("find files for pattern %q: %w", nginxLog.Path, err)
+		}
+
+		if len(paths) == 0 {
+			slog.Warn(fmt.Sprintf("no files for access log %q", nginxLog.Path))
+		}
+
+		for _, path := range paths {
+			if opened[path] {
+				continue
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				closeSources(sources)
+				return nil, fmt.Errorf("open file %q: %w", path, err)
+			}
+
+			opened[path] = true
+
+			sources = append(sources, newSource(path, f, format, nginxLog.FormatName))
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil, NewErrNoFiles(fmt.Sprintf("no access log files in %q", confPath))
+	}
+
+	return sources, nil
+}
+
+func getURLSource(client *httpClient, path string, format decoder, formatName string) (source, error) {
+	resp, err := client.get(path, 0)
+	if err != nil {
+		return source{}, err
+	}
+
+	body := &resumableBody{
+		client: client,
+		url:    path,
+		body:   resp.Body,
+	}
+
+	src := newSource(path, body, format, formatName)
+	src.compression = httpCompression(resp.Header)
+
+	return src, nil
+}
+
+func getFileSources(pattern string, format decoder, formatName string) ([]source, error) {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("find files for pattern %q: %w", pattern, err)
+	}
+
+	files, err := getFiles(paths)
+	if err != nil {
+		return nil, fmt.Errorf("getFiles(%q): %w", pattern, err)
+	}
+
+	sources := make([]source, len(files))
+	for i, f := range files {
+		sources[i] = newSource(paths[i], f, format, formatName)
+	}
+
+	return sources, nil
+}
+
+const stdinName = "stdin"
+
+func getStdinSource(stdin io.Reader, format decoder, formatName string) source {
+	if stdin == nil {
+		stdin = os.Stdin
+	}
+
+	return newSource(stdinName, io.NopCloser(stdin), format, formatName)
+}
+
+func getPathSources(
+	prm *Params,
+	client *httpClient,
+	path string,
+	format decoder,
+	formatName string,
+) ([]source, error) {
+	if path == StdinPath {
+		return []source{getStdinSource(prm.Stdin, format, formatName)}, nil
+	}
+
+	pathURL, err := parseURL(path)
+	if err == nil {
+		src, err := getURLSource(client, pathURL.String(), format, formatName)
+		if err != nil {
+			return nil, err
+		}
+
+		return []source{src}, nil
+	}
+
+	slog.Debug(fmt.Sprintf("parse %q as url: %s", path, err))
+
+	return getFileSources(path, format, formatName)
+}
+
+func (p *Parser) getSources(prm *Params) ([]source, error) {
+	sources := make([]source, 0, len(prm.Paths))
+
+	if prm.NginxConf != "" {
+		nginxSources, err := getNginxSources(prm.NginxConf, prm.JSONKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, nginxSources...)
+	}
+
+	if len(prm.Paths) == 0 {
+		if len(sources) == 0 {
+			return nil, NewErrNoFiles("no paths to read logs from")
+		}
+
+		return sources, nil
+	}
+
+	format, formatName, err := p.logFormat(prm)
+	if err != nil {
+		closeSources(sources)
+		return nil, err
+	}
+
+	client := newHTTPClient(prm.HTTP)
+
+	for _, path := range prm.Paths {
+		pathSources, err := getPathSources(prm, client, path, format, formatName)
+		if err != nil {
+			closeSources(sources)
+			return nil, err
+		}
+
+		sources = append(sources, pathSources...)
+	}
+
+	return sources, nil
+}
+
+func (p *Parser) openSources(prm *Params) ([]source, error) {
+	sources, err := p.getSources(prm)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sources {
+		if err := sources[i].decompress(); err != nil {
+			closeSources(sources)
+			return nil, err
+		}
+	}
+
+	p.detectFormats(sources, prm)
+
+	return sources, nil
+}
+
+func (p *Parser) parseSourcesFanOut(
+	ctx context.Context,
+	eg *errgroup.Group,
+	sources []source,
+) []<-chan line {
+	chs := make([]<-chan line, len(sources))
+
+	for i := range sources {
+		chs[i] = p.read(ctx, eg, &sources[i])
+	}
+
+	return chs
+}
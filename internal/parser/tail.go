@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const tailReadSize = 64 * 1024
+
+type tailPosition struct {
+	info   os.FileInfo
+	offset int64
+	number int
+}
+
+type tailer struct {
+	path        string
+	file        *os.File
+	reader      io.Reader
+	closer      io.Closer
+	compression string
+	info        os.FileInfo
+	offset      int64
+	number      int
+	partial     []byte
+	format      decoder
+	rotated     []tailPosition
+}
+
+func openTailer(path string, format decoder) (*tailer, error) {
+	t := &tailer{
+		path:   path,
+		format: format,
+	}
+
+	if err := t.open(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *tailer) open() error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return fmt.Errorf("open file %q: %w", t.path, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		closeResource(f)
+		return fmt.Errorf("stat file %q: %w", t.path, err)
+	}
+
+	t.file = f
+	t.reader = f
+	t.info = info
+	t.offset = 0
+	t.number = 0
+	t.partial = nil
+
+	return nil
+}
+
+func (t *tailer) sniff() error {
+	magic := make([]byte, sniffLength)
+
+	n, err := t.file.ReadAt(magic, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read head of %q: %w", t.path, err)
+	}
+
+	t.compression = magicCompression(magic[:n])
+
+	return nil
+}
+
+func (t *tailer) decompress() error {
+	reader, closer, err := decompress(bufio.NewReader(t.file), t.compression)
+	if err != nil {
+		return fmt.Errorf("decompress %q: %w", t.path, err)
+	}
+
+	t.reader = reader
+	t.closer = closer
+
+	return nil
+}
+
+func (t *tailer) resume(pos tailPosition) error {
+	if _, err := t.file.Seek(pos.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seek file %q: %w", t.path, err)
+	}
+
+	t.offset = pos.offset
+	t.number = pos.number
+
+	return nil
+}
+
+func (t *tailer) Close() error {
+	if t.closer != nil {
+		closeResource(t.closer)
+	}
+
+	if err := t.file.Close(); err != nil {
+		return fmt.Errorf("close file %q: %w", t.path, err)
+	}
+
+	return nil
+}
+
+func (t *tailer) readAvailable() ([]line, error) {
+	lines := make([]line, 0)
+	buf := make([]byte, tailReadSize)
+
+	for {
+		n, err := t.reader.Read(buf)
+		if n > 0 {
+			t.offset += int64(n)
+			lines = t.splitLines(buf[:n], lines)
+		}
+
+		if errors.Is(err, io.EOF) || n == 0 {
+			return lines, nil
+		}
+
+		if err != nil {
+			return lines, fmt.Errorf("read file %q: %w", t.path, err)
+		}
+	}
+}
+
+func (t *tailer) splitLines(chunk []byte, lines []line) []line {
+	for {
+		end := bytes.IndexByte(chunk, '\n')
+		if end == -1 {
+			t.partial = append(t.partial, chunk...)
+			return lines
+		}
+
+		text := string(bytes.TrimSuffix(append(t.partial, chunk[:end]...), []byte("\r")))
+		t.partial = nil
+		t.number++
+
+		lines = append(lines, newLine(text, t.number, t.path, t.format))
+		chunk = chunk[end+1:]
+	}
+}
+
+func (t *tailer) flushPartial(lines []line) []line {
+	if len(t.partial) == 0 {
+		return lines
+	}
+
+	t.number++
+	lines = append(lines, newLine(string(t.partial), t.number, t.path, t.format))
+	t.partial = nil
+
+	return lines
+}
+
+func (t *tailer) poll() ([]line, error) {
+	lines, err := t.readAvailable()
+	if err != nil {
+		return lines, err
+	}
+
+	if t.compression != compressionNone {
+		return t.flushPartial(lines), nil
+	}
+
+	info, err := os.Stat(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lines, nil
+	}
+
+	if err != nil {
+		return lines, fmt.Errorf("stat file %q: %w", t.path, err)
+	}
+
+	switch {
+	case !os.SameFile(info, t.info):
+		lines = t.flushPartial(lines)
+
+		t.rotated = append(t.rotated, tailPosition{info: t.info, offset: t.offset, number: t.number})
+
+		closeResource(t.file)
+
+		if err := t.open(); err != nil {
+			return lines, err
+		}
+
+		rotated, err := t.readAvailable()
+
+		return append(lines, rotated...), err
+
+	case info.Size() < t.offset:
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return lines, fmt.Errorf("seek file %q: %w", t.path, err)
+		}
+
+		t.offset = 0
+		t.number = 0
+		t.partial = nil
+
+		truncated, err := t.readAvailable()
+
+		return append(lines, truncated...), err
+	}
+
+	return lines, nil
+}
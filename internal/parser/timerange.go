@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dayDuration = 24 * time.Hour
+
+var timeBoundLayouts = []string{
+	time.RFC3339Nano,
+	timeLocalLayout,
+	"2006-01-02T15:04:05",
+	time.DateTime,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"02/Jan/2006:15:04:05",
+}
+
+var dayLayouts = []string{
+	time.DateOnly,
+	timeLayout,
+}
+
+var clockLayouts = []string{
+	"15:04:05",
+	"15:04",
+}
+
+func parseDuration(value string) (time.Duration, bool) {
+	var days time.Duration
+
+	if before, after, ok := strings.Cut(value, "d"); ok {
+		count, err := strconv.Atoi(before)
+		if err != nil || count < 0 {
+			return 0, false
+		}
+
+		days, value = time.Duration(count)*dayDuration, after
+		if value == "" {
+			return days, true
+		}
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, false
+	}
+
+	return days + duration, true
+}
+
+func midnight(tm time.Time) time.Time {
+	return time.Date(tm.Year(), tm.Month(), tm.Day(), 0, 0, 0, 0, tm.Location())
+}
+
+func parseDayRelative(value string, now time.Time) (time.Time, bool, bool) {
+	name, clock, hasClock := strings.Cut(value, " ")
+
+	var start time.Time
+
+	switch name {
+	case "today":
+		start = midnight(now)
+
+	case "yesterday":
+		start = midnight(now).AddDate(0, 0, -1)
+
+	default:
+		return time.Time{}, false, false
+	}
+
+	if !hasClock {
+		return start, true, true
+	}
+
+	for _, layout := range clockLayouts {
+		if tm, err := time.Parse(layout, strings.TrimSpace(clock)); err == nil {
+			return time.Date(start.Year(), start.Month(), start.Day(), tm.Hour(), tm.Minute(), tm.Second(), 0, start.Location()), false, true
+		}
+	}
+
+	return time.Time{}, false, false
+}
+
+func parseTimeBound(value string, now time.Time) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+
+	if value == "now" {
+		return now, false, nil
+	}
+
+	if ago, ok := strings.CutPrefix(value, "-"); ok {
+		duration, ok := parseDuration(ago)
+		if !ok {
+			return time.Time{}, false, NewErrTimeRange(fmt.Sprintf("bad relative time %q", value))
+		}
+
+		return now.Add(-duration), false, nil
+	}
+
+	if tm, wholeDay, ok := parseDayRelative(value, now); ok {
+		return tm, wholeDay, nil
+	}
+
+	for _, layout := range timeBoundLayouts {
+		if tm, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return tm, false, nil
+		}
+	}
+
+	for _, layout := range dayLayouts {
+		if tm, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return tm, true, nil
+		}
+	}
+
+	return time.Time{}, false, NewErrTimeRange(fmt.Sprintf("bad time %q", value))
+}
+
+func ParseTimeRange(from, to, last string, now time.Time) (*time.Time, *time.Time, error) {
+	var start, end *time.Time
+
+	if last != "" {
+		if from != "" {
+			return nil, nil, NewErrTimeRange("last and from can not be used together")
+		}
+
+		duration, ok := parseDuration(last)
+		if !ok || duration == 0 {
+			return nil, nil, NewErrTimeRange(fmt.Sprintf("bad duration %q", last))
+		}
+
+		from = "-" + last
+	}
+
+	if from != "" {
+		tm, _, err := parseTimeBound(from, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse from: %w", err)
+		}
+
+		start = &tm
+	}
+
+	if to != "" {
+		tm, wholeDay, err := parseTimeBound(to, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse to: %w", err)
+		}
+
+		if wholeDay {
+			tm = tm.AddDate(0, 0, 1)
+		}
+
+		end = &tm
+	}
+
+	if start != nil && end != nil && !start.Before(*end) {
+		return nil, nil, NewErrTimeRange(fmt.Sprintf("from %s is not before to %s", start.Format(time.RFC3339), end.Format(time.RFC3339)))
+	}
+
+	return start, end, nil
+}
+
+func hasTimeVariable(variables []string) bool {
+	return slices.Contains(variables, "time_local") || slices.Contains(variables, "time_iso8601")
+}
+
+func checkTimeRange(prm *Params, decoders ...decoder) error {
+	if prm.From == nil && prm.To == nil {
+		return nil
+	}
+
+	for _, dec := range decoders {
+		if format, ok := dec.(*Format); ok && !hasTimeVariable(format.Variables()) {
+			return NewErrTimeRange("time range needs $time_local or $time_iso8601 in the log format")
+		}
+	}
+
+	return nil
+}
+
+func inTimeRange(tm time.Time, from, to *time.Time) bool {
+	return (from == nil || !tm.Before(*from)) && (to == nil || tm.Before(*to))
+}
@@ -0,0 +1,299 @@
+package parser_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	zone := time.FixedZone("MSK", 3*60*60)
+	now := time.Date(2024, time.October, 23, 12, 30, 0, 0, zone)
+
+	tt := []struct {
+		name string
+		from string
+		to   string
+		last string
+		want [2]time.Time
+	}{
+		{
+			name: "rfc3339 with zone",
+			from: "2024-10-22T09:00:00Z",
+			to:   "2024-10-22T09:15:00.5+00:00",
+			want: [2]time.Time{
+				time.Date(2024, time.October, 22, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, time.October, 22, 9, 15, 0, 500_000_000, time.UTC),
+			},
+		},
+		{
+			name: "time without zone",
+			from: "2024-10-22T09:00",
+			to:   "2024-10-22 09:15:30",
+			want: [2]time.Time{
+				time.Date(2024, time.October, 22, 9, 0, 0, 0, zone),
+				time.Date(2024, time.October, 22, 9, 15, 30, 0, zone),
+			},
+		},
+		{
+			name: "nginx time_local",
+			from: "22/Oct/2024:09:00:00 +0000",
+			to:   "22/Oct/2024:14:15:00",
+			want: [2]time.Time{
+				time.Date(2024, time.October, 22, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, time.October, 22, 14, 15, 0, 0, zone),
+			},
+		},
+		{
+			name: "dates include the whole to day",
+			from: "2024-10-21",
+			to:   "22/Oct/2024",
+			want: [2]time.Time{
+				time.Date(2024, time.October, 21, 0, 0, 0, 0, zone),
+				time.Date(2024, time.October, 23, 0, 0, 0, 0, zone),
+			},
+		},
+		{
+			name: "relative durations",
+			from: "-1d2h",
+			to:   "-30m",
+			want: [2]time.Time{
+				time.Date(2024, time.October, 22, 10, 30, 0, 0, zone),
+				time.Date(2024, time.October, 23, 12, 0, 0, 0, zone),
+			},
+		},
+		{
+			name: "relative days",
+			from: "yesterday 09:00",
+			to:   "yesterday",
+			want: [2]time.Time{
+				time.Date(2024, time.October, 22, 9, 0, 0, 0, zone),
+				time.Date(2024, time.October, 23, 0, 0, 0, 0, zone),
+			},
+		},
+		{
+			name: "last",
+			last: "30m",
+			to:   "now",
+			want: [2]time.Time{
+				time.Date(2024, time.October, 23, 12, 0, 0, 0, zone),
+				now,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, err := parser.ParseTimeRange(tc.from, tc.to, tc.last, now)
+			require.NoError(t, err, "time range must be parsed")
+			require.NotNil(t, from)
+			require.NotNil(t, to)
+
+			assert.True(t, tc.want[0].Equal(*from), "from: %s", from)
+			assert.True(t, tc.want[1].Equal(*to), "to: %s", to)
+		})
+	}
+}
+
+func TestParseTimeRangeError(t *testing.T) {
+	now := time.Date(2024, time.October, 23, 12, 30, 0, 0, time.UTC)
+
+	tt := []struct {
+		name string
+		from string
+		to   string
+		last string
+		msg  string
+	}{
+		{
+			name: "bad time",
+			from: "22.10.2024",
+			msg:  `parse from: bad time "22.10.2024"`,
+		},
+		{
+			name: "bad relative time",
+			to:   "-2 hours",
+			msg:  `parse to: bad relative time "-2 hours"`,
+		},
+		{
+			name: "bad clock",
+			from: "today 25:00",
+			msg:  `parse from: bad time "today 25:00"`,
+		},
+		{
+			name: "last with from",
+			from: "-1h",
+			last: "30m",
+			msg:  "last and from can not be used together",
+		},
+		{
+			name: "bad last",
+			last: "0s",
+			msg:  `bad duration "0s"`,
+		},
+		{
+			name: "empty range",
+			from: "2024-10-22T10:00:00Z",
+			to:   "2024-10-22T10:00:00Z",
+			msg:  "from 2024-10-22T10:00:00Z is not before to 2024-10-22T10:00:00Z",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := parser.ParseTimeRange(tc.from, tc.to, tc.last, now)
+			require.ErrorAs(t, err, &parser.ErrTimeRange{})
+			assert.EqualError(t, err, tc.msg)
+		})
+	}
+}
+
+func TestParseWithTimestampRange(t *testing.T) {
+	fileName := createTestFiles(t, filterLogs)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	from, to, err := parser.ParseTimeRange("22/Oct/2024:09:15:00 +0000", "2024-10-22T09:30:00Z", "", time.Now())
+	require.NoError(t, err, "time range must be parsed")
+
+	data, err := parser.New().Parse(parser.Params{
+		Paths:     []string{fileName},
+		LogFormat: filterLogFormat,
+		From:      from,
+		To:        to,
+	})
+	require.NoError(t, err, "file must be parsed")
+
+	assert.Equal(t, 1, data.TotalRequests, "from is inclusive and to is exclusive")
+	assert.Equal(t, "/b", data.FrequentURLs[0].Name)
+}
+
+func TestParseWithTimeZone(t *testing.T) {
+	content := `10.0.0.1 - - [22/Oct/2024:23:30:00 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0"` + "\n" +
+		`10.0.0.2 - - [23/Oct/2024:00:30:00 +0300] "GET /b HTTP/1.1" 200 100 "-" "curl/8.0"` + "\n"
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err, "time zone must be loaded")
+
+	tt := []struct {
+		name     string
+		timeZone *time.Location
+		filter   string
+		zoneName string
+		days     []string
+	}{
+		{
+			name: "offsets of the lines",
+			days: []string{"2024-10-22", "2024-10-23"},
+		},
+		{
+			name:     "utc",
+			timeZone: time.UTC,
+			zoneName: "UTC",
+			days:     []string{"2024-10-22"},
+		},
+		{
+			name:     "iana zone",
+			timeZone: tokyo,
+			zoneName: "Asia/Tokyo",
+			days:     []string{"2024-10-23"},
+		},
+		{
+			name:     "filter times in the zone",
+			timeZone: tokyo,
+			filter:   `TimeLocal >= "2024-10-23 08:00:00" && TimeLocal ~ "^23/Oct/2024:08:30:00 \\+0900$"`,
+			zoneName: "Asia/Tokyo",
+			days:     []string{"2024-10-23"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fileName := createTestFiles(t, content)
+			defer deleteTestFiles(t, getRoot(fileName))
+
+			data, err := parser.New().Parse(parser.Params{
+				Paths:    []string{fileName},
+				TimeZone: tc.timeZone,
+				Filter:   tc.filter,
+			})
+			require.NoError(t, err, "file must be parsed")
+
+			days := make([]string, len(data.RequestsPerDay))
+			for i, day := range data.RequestsPerDay {
+				days[i] = day.Day
+			}
+
+			assert.Equal(t, tc.days, days)
+			assert.Equal(t, tc.zoneName, data.TimeZone)
+		})
+	}
+}
+
+func TestParseTimesWithoutZoneInLocalTime(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC+3", 3*60*60)
+
+	t.Cleanup(func() { time.Local = local })
+
+	content := `10.0.0.1 - - [22/Oct/2024:08:30:00 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0"` + "\n" +
+		`10.0.0.2 - - [22/Oct/2024:09:30:00 +0000] "GET /b HTTP/1.1" 200 100 "-" "curl/8.0"` + "\n"
+
+	fileName := createTestFiles(t, content)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	from, _, err := parser.ParseTimeRange("2024-10-22 12:00:00", "", "", time.Now())
+	require.NoError(t, err, "time range must be parsed")
+
+	tt := []struct {
+		name string
+		prm  parser.Params
+	}{
+		{
+			name: "from",
+			prm:  parser.Params{Paths: []string{fileName}, From: from},
+		},
+		{
+			name: "filter",
+			prm:  parser.Params{Paths: []string{fileName}, Filter: `TimeLocal >= "2024-10-22 12:00:00"`},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := parser.New().Parse(tc.prm)
+			require.NoError(t, err, "file must be parsed")
+
+			assert.Equal(t, 1, data.TotalRequests)
+			assert.Equal(t, "/b", data.FrequentURLs[0].Name)
+		})
+	}
+}
+
+func TestParseFormatWithoutTime(t *testing.T) {
+	fileName := createTestFiles(t, "10.0.0.1 200\n10.0.0.2 404\n")
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	data, err := parser.New().Parse(parser.Params{
+		Paths:     []string{fileName},
+		LogFormat: "$remote_addr $status",
+	})
+	require.NoError(t, err, "file must be parsed")
+
+	assert.Equal(t, 2, data.TotalRequests)
+	assert.Empty(t, data.RequestsPerDay, "requests without a time must not be counted per day")
+	assert.Equal(t, 0, data.AvgResponsePerDay)
+
+	from, _, err := parser.ParseTimeRange("-1h", "", "", time.Now())
+	require.NoError(t, err, "time range must be parsed")
+
+	_, err = parser.New().Parse(parser.Params{
+		Paths:     []string{fileName},
+		LogFormat: "$remote_addr $status",
+		From:      from,
+	})
+	require.ErrorAs(t, err, &parser.ErrTimeRange{})
+	assert.EqualError(t, err, "time range needs $time_local or $time_iso8601 in the log format")
+}
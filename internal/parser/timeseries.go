@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+)
+
+const (
+	hllBucketPrecision = 10
+	maxTimeBuckets     = 100_000
+
+	bucketLayout    = "2006-01-02 15:04"
+	bucketDayLayout = time.DateOnly
+)
+
+var bucketSizes = []struct {
+	names []string
+	size  time.Duration
+}{
+	{names: []string{"minute", "1m"}, size: time.Minute},
+	{names: []string{"5m"}, size: 5 * time.Minute},
+	{names: []string{"hour", "1h"}, size: time.Hour},
+	{names: []string{"day", "1d"}, size: dayDuration},
+}
+
+func ParseBucketSize(name string) (time.Duration, error) {
+	if name == "" {
+		return 0, nil
+	}
+
+	for _, bucket := range bucketSizes {
+		for _, known := range bucket.names {
+			if name == known {
+				return bucket.size, nil
+			}
+		}
+	}
+
+	return 0, NewErrTimeSeries(fmt.Sprintf("unknown bucket size %q, use minute, 5m, hour or day", name))
+}
+
+func bucketName(size time.Duration) string {
+	for _, bucket := range bucketSizes {
+		if bucket.size == size {
+			return bucket.names[0]
+		}
+	}
+
+	return size.String()
+}
+
+type timeBucket struct {
+	counter
+	Addresses *hyperLogLog `json:"addresses"`
+}
+
+func bucketStart(tm time.Time, size time.Duration, loc *time.Location) time.Time {
+	if size >= dayDuration {
+		return time.Date(tm.Year(), tm.Month(), tm.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	_, offset := tm.In(loc).Zone()
+	shift := time.Duration(offset) * time.Second
+
+	return tm.UTC().Add(shift).Truncate(size).Add(-shift)
+}
+
+func (d *data) countBucket(logEntry *log) error {
+	if logEntry.TimeLocal.IsZero() {
+		return nil
+	}
+
+	start := bucketStart(logEntry.TimeLocal, d.bucket, d.location)
+	key := start.Format(bucketLayout)
+
+	bucket, ok := d.timeSeries[key]
+	if !ok {
+		first, last := d.seriesFirst, d.seriesLast
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+
+		if last.IsZero() || start.After(last) {
+			last = start
+		}
+
+		if err := d.checkBuckets(first, last); err != nil {
+			return err
+		}
+
+		d.seriesFirst, d.seriesLast = first, last
+		bucket = &timeBucket{Addresses: newHyperLogLog(hllBucketPrecision)}
+		d.timeSeries[key] = bucket
+	}
+
+	bucket.add(logEntry)
+	bucket.Addresses.add(logEntry.RemoteAddress)
+
+	return nil
+}
+
+func nextBucket(start time.Time, size time.Duration) time.Time {
+	if size >= dayDuration {
+		return start.AddDate(0, 0, 1)
+	}
+
+	return start.Add(size)
+}
+
+func bucketLabel(start time.Time, size time.Duration, loc *time.Location) string {
+	if size >= dayDuration {
+		return start.Format(bucketDayLayout)
+	}
+
+	return start.In(loc).Format(bucketLayout)
+}
+
+func newTimeBucket(label string, bucket *timeBucket) domain.TimeBucket {
+	if bucket == nil {
+		return domain.NewTimeBucket(label, 0, 0, 0, 0)
+	}
+
+	return domain.NewTimeBucket(label, bucket.Requests, bucket.Bytes, bucket.Errors, bucket.Addresses.count())
+}
+
+func seriesRange(parseData *data) (time.Time, time.Time, bool) {
+	if parseData.bucket <= 0 || len(parseData.timeSeries) == 0 {
+		return time.Time{}, time.Time{}, false
+	}
+
+	first, last := "", ""
+
+	for key := range parseData.timeSeries {
+		if first == "" || key < first {
+			first = key
+		}
+
+		if key > last {
+			last = key
+		}
+	}
+
+	start, err := time.Parse(bucketLayout, first)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	end, err := time.Parse(bucketLayout, last)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	return start, end, true
+}
+
+func bucketCount(first, end time.Time, size time.Duration) int64 {
+	return int64(end.Sub(first)/size) + 1
+}
+
+func (d *data) checkBuckets(first, end time.Time) error {
+	if count := bucketCount(first, end, d.bucket); count > maxTimeBuckets {
+		return NewErrTimeSeries(fmt.Sprintf(
+			"%d %s buckets from %s to %s exceed the limit of %d, use a bigger bucket size or a time range",
+			count, bucketName(d.bucket), bucketLabel(first, d.bucket, d.location), bucketLabel(end, d.bucket, d.location),
+			maxTimeBuckets,
+		))
+	}
+
+	return nil
+}
+
+func (d *data) restoreSeriesRange() {
+	d.seriesFirst, d.seriesLast, _ = seriesRange(d)
+}
+
+func timeSeries(parseData *data) *domain.TimeSeries {
+	first, end := parseData.seriesFirst, parseData.seriesLast
+	if parseData.bucket <= 0 || first.IsZero() {
+		return nil
+	}
+
+	count := bucketCount(first, end, parseData.bucket)
+	if count > maxTimeBuckets {
+		return nil
+	}
+
+	buckets := make([]domain.TimeBucket, 0, count)
+	peak, quietest := 0, 0
+
+	for start := first; !start.After(end); start = nextBucket(start, parseData.bucket) {
+		label := bucketLabel(start, parseData.bucket, parseData.location)
+		buckets = append(buckets, newTimeBucket(label, parseData.timeSeries[start.Format(bucketLayout)]))
+
+		i := len(buckets) - 1
+		if buckets[i].Quantity > buckets[peak].Quantity {
+			peak = i
+		}
+
+		if buckets[i].Quantity < buckets[quietest].Quantity {
+			quietest = i
+		}
+	}
+
+	return domain.NewTimeSeries(bucketName(parseData.bucket), buckets, buckets[peak], buckets[quietest])
+}
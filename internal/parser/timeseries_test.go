@@ -0,0 +1,193 @@
+package parser_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeSeries(t *testing.T) {
+	tt := []struct {
+		name     string
+		bucket   string
+		count    int
+		first    domain.TimeBucket
+		peak     domain.TimeBucket
+		quietest domain.TimeBucket
+	}{
+		{
+			name:     "minute",
+			bucket:   "minute",
+			count:    24*60 + 1,
+			first:    domain.NewTimeBucket("2024-10-22 09:00", 1, 100, 0, 1),
+			peak:     domain.NewTimeBucket("2024-10-22 09:00", 1, 100, 0, 1),
+			quietest: domain.NewTimeBucket("2024-10-22 09:01", 0, 0, 0, 0),
+		},
+		{
+			name:     "five minutes",
+			bucket:   "5m",
+			count:    24*12 + 1,
+			first:    domain.NewTimeBucket("2024-10-22 09:00", 1, 100, 0, 1),
+			peak:     domain.NewTimeBucket("2024-10-22 09:00", 1, 100, 0, 1),
+			quietest: domain.NewTimeBucket("2024-10-22 09:05", 0, 0, 0, 0),
+		},
+		{
+			name:     "hour",
+			bucket:   "hour",
+			count:    25,
+			first:    domain.NewTimeBucket("2024-10-22 09:00", 3, 600, 2, 3),
+			peak:     domain.NewTimeBucket("2024-10-22 09:00", 3, 600, 2, 3),
+			quietest: domain.NewTimeBucket("2024-10-22 11:00", 0, 0, 0, 0),
+		},
+		{
+			name:     "day",
+			bucket:   "day",
+			count:    2,
+			first:    domain.NewTimeBucket("2024-10-22", 4, 1000, 3, 4),
+			peak:     domain.NewTimeBucket("2024-10-22", 4, 1000, 3, 4),
+			quietest: domain.NewTimeBucket("2024-10-23", 1, 0, 1, 1),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fileName := createTestFiles(t, filterLogs)
+			defer deleteTestFiles(t, getRoot(fileName))
+
+			bucket, err := parser.ParseBucketSize(tc.bucket)
+			require.NoError(t, err, "bucket size must be parsed")
+
+			data, err := parser.New().Parse(parser.Params{
+				Paths:     []string{fileName},
+				LogFormat: filterLogFormat,
+				Bucket:    bucket,
+			})
+			require.NoError(t, err, "file must be parsed")
+			require.NotNil(t, data.TimeSeries)
+
+			assert.Equal(t, tc.bucket, data.TimeSeries.Bucket)
+			assert.Len(t, data.TimeSeries.Buckets, tc.count)
+			assert.Equal(t, tc.first, data.TimeSeries.Buckets[0])
+			assert.Equal(t, tc.peak, data.TimeSeries.Peak)
+			assert.Equal(t, tc.quietest, data.TimeSeries.Quietest)
+		})
+	}
+}
+
+func TestParseTimeSeriesInTimeZone(t *testing.T) {
+	fileName := createTestFiles(t, filterLogs)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err, "time zone must be loaded")
+
+	data, err := parser.New().Parse(parser.Params{
+		Paths:     []string{fileName},
+		LogFormat: filterLogFormat,
+		TimeZone:  kolkata,
+		Bucket:    time.Hour,
+	})
+	require.NoError(t, err, "file must be parsed")
+	require.NotNil(t, data.TimeSeries)
+
+	assert.Equal(t, domain.NewTimeBucket("2024-10-22 14:00", 2, 300, 1, 2), data.TimeSeries.Buckets[0])
+	assert.Equal(t, domain.NewTimeBucket("2024-10-22 15:00", 2, 700, 2, 2), data.TimeSeries.Buckets[1])
+}
+
+func TestParseTimeSeriesDaylightSavingTime(t *testing.T) {
+	content := `10.0.0.1 - - [03/Nov/2024:05:30:00 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0"` + "\n" +
+		`10.0.0.2 - - [03/Nov/2024:06:30:00 +0000] "GET /b HTTP/1.1" 200 200 "-" "curl/8.0"` + "\n" +
+		`10.0.0.3 - - [03/Nov/2024:07:10:00 +0000] "GET /c HTTP/1.1" 200 300 "-" "curl/8.0"` + "\n"
+
+	fileName := createTestFiles(t, content)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err, "time zone must be loaded")
+
+	data, err := parser.New().Parse(parser.Params{
+		Paths:    []string{fileName},
+		TimeZone: newYork,
+		Bucket:   time.Hour,
+	})
+	require.NoError(t, err, "file must be parsed")
+	require.NotNil(t, data.TimeSeries)
+
+	assert.Equal(t, []domain.TimeBucket{
+		domain.NewTimeBucket("2024-11-03 01:00", 1, 100, 0, 1),
+		domain.NewTimeBucket("2024-11-03 01:00", 1, 200, 0, 1),
+		domain.NewTimeBucket("2024-11-03 02:00", 1, 300, 0, 1),
+	}, data.TimeSeries.Buckets, "repeated hours must be separate buckets")
+}
+
+func TestParseTimeSeriesWithStateFile(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "access.log")
+	prm := parser.Params{
+		Paths:     []string{logPath},
+		StateFile: filepath.Join(dir, "state.json"),
+		Bucket:    time.Hour,
+	}
+
+	appendFile(t, logPath, logLine("10.0.0.1", "/a", 100))
+
+	_, err := parser.New().Parse(prm)
+	require.NoError(t, err, "logs must be parsed")
+
+	appendFile(t, logPath, logLine("10.0.0.2", "/b", 200))
+
+	data, err := parser.New().Parse(prm)
+	require.NoError(t, err, "logs must be parsed")
+	require.NotNil(t, data.TimeSeries)
+
+	assert.Equal(t, []domain.TimeBucket{domain.NewTimeBucket("2024-10-22 09:00", 2, 300, 0, 2)}, data.TimeSeries.Buckets)
+}
+
+func TestParseTimeSeriesWithoutTime(t *testing.T) {
+	fileName := createTestFiles(t, "10.0.0.1 200\n10.0.0.2 404\n")
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	data, err := parser.New().Parse(parser.Params{
+		Paths:     []string{fileName},
+		LogFormat: "$remote_addr $status",
+		Bucket:    time.Minute,
+	})
+	require.NoError(t, err, "file must be parsed")
+
+	assert.Equal(t, 2, data.TotalRequests)
+	assert.Nil(t, data.TimeSeries, "requests without a time must not be bucketed")
+}
+
+func TestParseTimeSeriesTooManyBuckets(t *testing.T) {
+	content := `10.0.0.1 - - [22/Oct/2023:09:00:00 +0000] "GET /a HTTP/1.1" 200 100 "-" "curl/8.0"` + "\n" +
+		`10.0.0.2 - - [22/Oct/2024:09:00:00 +0000] "GET /b HTTP/1.1" 200 100 "-" "curl/8.0"` + "\n"
+
+	fileName := createTestFiles(t, content)
+	defer deleteTestFiles(t, getRoot(fileName))
+
+	_, err := parser.New().Parse(parser.Params{
+		Paths:  []string{fileName},
+		Bucket: time.Minute,
+	})
+	require.ErrorAs(t, err, &parser.ErrTimeSeries{})
+	assert.EqualError(t, err, "eg.Wait(): 527041 minute buckets from 2023-10-22 09:00 to 2024-10-22 09:00 exceed the limit of 100000, "+
+		"use a bigger bucket size or a time range")
+
+	data, err := parser.New().Parse(parser.Params{
+		Paths:  []string{fileName},
+		Bucket: 24 * time.Hour,
+	})
+	require.NoError(t, err, "file must be parsed")
+	assert.Len(t, data.TimeSeries.Buckets, 367)
+}
+
+func TestParseBucketSizeError(t *testing.T) {
+	_, err := parser.ParseBucketSize("week")
+	require.ErrorAs(t, err, &parser.ErrTimeSeries{})
+	assert.EqualError(t, err, `unknown bucket size "week", use minute, 5m, hour or day`)
+}
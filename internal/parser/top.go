@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+type TopMetric string
+
+const (
+	TopByCount  TopMetric = "count"
+	TopByBytes  TopMetric = "bytes"
+	TopByErrors TopMetric = "errors"
+)
+
+const defaultTopLimit = 3
+
+func ParseTopMetric(metric string) (TopMetric, error) {
+	switch TopMetric(metric) {
+	case "", TopByCount:
+		return TopByCount, nil
+
+	case TopByBytes, TopByErrors:
+		return TopMetric(metric), nil
+	}
+
+	return TopByCount, NewErrTop(fmt.Sprintf("unknown metric %q", metric))
+}
+
+type TopOptions struct {
+	URLs      int
+	Statuses  int
+	Addresses int
+	Metric    TopMetric
+	Other     bool
+	Capacity  int
+}
+
+func (o *TopOptions) metric() TopMetric {
+	if o.Metric == "" {
+		return TopByCount
+	}
+
+	return o.Metric
+}
+
+type counter struct {
+	Requests int `json:"requests"`
+	Bytes    int `json:"bytes"`
+	Errors   int `json:"errors"`
+}
+
+func isError(status int) bool {
+	return status >= http.StatusBadRequest
+}
+
+func (c *counter) add(logEntry *log) {
+	c.Requests++
+	c.Bytes += logEntry.BodyBytesSend
+
+	if isError(logEntry.Status) {
+		c.Errors++
+	}
+}
+
+func (c *counter) merge(other *counter) {
+	c.Requests += other.Requests
+	c.Bytes += other.Bytes
+	c.Errors += other.Errors
+}
+
+func (c *counter) value(metric TopMetric) int {
+	switch metric {
+	case TopByBytes:
+		return c.Bytes
+
+	case TopByErrors:
+		return c.Errors
+
+	default:
+		return c.Requests
+	}
+}
+
+func countKey[K comparable](counters map[K]*counter, key K, logEntry *log) {
+	cnt, ok := counters[key]
+	if !ok {
+		cnt = &counter{}
+		counters[key] = cnt
+	}
+
+	cnt.add(logEntry)
+}
+
+type topEntry[K cmp.Ordered] struct {
+	key K
+	counter
+}
+
+func topEntries[K cmp.Ordered](
+	counters map[K]*counter,
+	limit int,
+	opts *TopOptions,
+) ([]topEntry[K], *counter) {
+	metric := opts.metric()
+
+	entries := make([]topEntry[K], 0, len(counters))
+	for key, cnt := range counters {
+		entries = append(entries, topEntry[K]{key: key, counter: *cnt})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if a, b := entries[i].value(metric), entries[j].value(metric); a != b {
+			return a > b
+		}
+
+		if entries[i].Requests != entries[j].Requests {
+			return entries[i].Requests > entries[j].Requests
+		}
+
+		return entries[i].key < entries[j].key
+	})
+
+	if limit == 0 {
+		limit = defaultTopLimit
+	}
+
+	if limit < 0 || limit >= len(entries) {
+		return entries, nil
+	}
+
+	if !opts.Other {
+		return entries[:limit], nil
+	}
+
+	other := &counter{}
+	for i := limit; i < len(entries); i++ {
+		other.merge(&entries[i].counter)
+	}
+
+	return entries[:limit], other
+}
@@ -0,0 +1,152 @@
+package parser_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statusLine(address, url string, status, size int) string {
+	return fmt.Sprintf(`%s - - [22/Oct/2024:09:48:45 +0000] "GET %s HTTP/1.1" %d %d "-" "curl/8.0"`+"\n",
+		address, url, status, size)
+}
+
+func TestParseTop(t *testing.T) {
+	content := statusLine("10.0.0.1", "/a", 200, 100) +
+		statusLine("10.0.0.1", "/a", 200, 100) +
+		statusLine("10.0.0.2", "/a", 200, 100) +
+		statusLine("10.0.0.2", "/b", 500, 1000) +
+		statusLine("10.0.0.3", "/c", 404, 50) +
+		statusLine("10.0.0.3", "/c", 404, 50) +
+		statusLine("10.0.0.4", "/d", 200, 10)
+
+	tt := []struct {
+		name              string
+		top               parser.TopOptions
+		frequentURLs      []domain.URL
+		frequentStatuses  []domain.Status
+		frequentAddresses []domain.Address
+	}{
+		{
+			name: "count with other",
+			top: parser.TopOptions{
+				URLs:      2,
+				Statuses:  1,
+				Addresses: 5,
+				Other:     true,
+			},
+			frequentURLs: []domain.URL{
+				domain.NewURL("/a", 3),
+				domain.NewURL("/c", 2),
+				domain.NewURL(domain.OtherName, 2),
+			},
+			frequentStatuses: []domain.Status{
+				domain.NewStatus(200, 4),
+				domain.NewOtherStatus(3),
+			},
+			frequentAddresses: []domain.Address{
+				domain.NewAddress("10.0.0.1", 2),
+				domain.NewAddress("10.0.0.2", 2),
+				domain.NewAddress("10.0.0.3", 2),
+				domain.NewAddress("10.0.0.4", 1),
+			},
+		},
+		{
+			name: "bytes",
+			top: parser.TopOptions{
+				URLs:      2,
+				Statuses:  -1,
+				Addresses: 1,
+				Metric:    parser.TopByBytes,
+				Other:     true,
+			},
+			frequentURLs: []domain.URL{
+				{Name: "/b", Quantity: 1, Bytes: 1000},
+				{Name: "/a", Quantity: 3, Bytes: 300},
+				{Name: domain.OtherName, Quantity: 3, Bytes: 110},
+			},
+			frequentStatuses: []domain.Status{
+				{Code: 500, Name: "Internal Server Error", Quantity: 1, Bytes: 1000},
+				{Code: 200, Name: "OK", Quantity: 4, Bytes: 310},
+				{Code: 404, Name: "Not Found", Quantity: 2, Bytes: 100},
+			},
+			frequentAddresses: []domain.Address{
+				{Name: "10.0.0.2", Quantity: 2, Bytes: 1100},
+				{Name: domain.OtherName, Quantity: 5, Bytes: 310},
+			},
+		},
+		{
+			name: "errors",
+			top: parser.TopOptions{
+				URLs:   -1,
+				Metric: parser.TopByErrors,
+			},
+			frequentURLs: []domain.URL{
+				{Name: "/c", Quantity: 2, Errors: 2},
+				{Name: "/b", Quantity: 1, Errors: 1},
+				domain.NewURL("/a", 3),
+				domain.NewURL("/d", 1),
+			},
+			frequentStatuses: []domain.Status{
+				{Code: 404, Name: "Not Found", Quantity: 2, Errors: 2},
+				{Code: 500, Name: "Internal Server Error", Quantity: 1, Errors: 1},
+				domain.NewStatus(200, 4),
+			},
+			frequentAddresses: []domain.Address{
+				{Name: "10.0.0.3", Quantity: 2, Errors: 2},
+				{Name: "10.0.0.2", Quantity: 2, Errors: 1},
+				domain.NewAddress("10.0.0.1", 2),
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fileName := createTestFiles(t, content)
+			defer deleteTestFiles(t, getRoot(fileName))
+
+			logParser := parser.New()
+
+			data, err := logParser.Parse(parser.Params{
+				Paths: []string{fileName},
+				Top:   tc.top,
+			})
+			require.NoError(t, err, "file must be parsed")
+
+			assert.Equal(t, tc.frequentURLs, data.FrequentURLs)
+			assert.Equal(t, tc.frequentStatuses, data.FrequentStatuses)
+			assert.Equal(t, tc.frequentAddresses, data.FrequentAddresses)
+		})
+	}
+}
+
+func TestParseTopMetric(t *testing.T) {
+	tt := []struct {
+		metric   string
+		expected parser.TopMetric
+		wantErr  bool
+	}{
+		{metric: "", expected: parser.TopByCount},
+		{metric: "count", expected: parser.TopByCount},
+		{metric: "bytes", expected: parser.TopByBytes},
+		{metric: "errors", expected: parser.TopByErrors},
+		{metric: "latency", wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.metric, func(t *testing.T) {
+			metric, err := parser.ParseTopMetric(tc.metric)
+			if tc.wantErr {
+				require.ErrorAs(t, err, &parser.ErrTop{})
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, metric)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+)
+
+func Adoc(info *domain.FileInfo, out io.Writer) error {
+	fmt.Fprint(out, "==== General Information\n\n")
+	fmt.Fprint(out, "[options=\"header\"]\n")
+	fmt.Fprint(out, "|===\n")
+	fmt.Fprint(out, "| Метрика | Значение\n")
+
+	fmt.Fprintf(out, "| Files | %s\n", strings.Join(info.Paths, ", "))
+
+	if info.TimeZone != "" {
+		fmt.Fprintf(out, "| Time zone | %s\n", info.TimeZone)
+	}
+
+	fmt.Fprintf(out, "| Number of requests | %d\n", info.TotalRequests)
+	fmt.Fprintf(out, "| Average response size | %d\n", info.AvgResponseSize)
+	fmt.Fprintf(out, "| 95th percentile of response size | %d\n", info.ResponseSize95p)
+	fmt.Fprintf(out, "| Average requests per day | %d |\n", info.AvgResponsePerDay)
+	fmt.Fprintf(out, "| Unique addresses | %d\n", info.UniqueAddresses)
+	fmt.Fprintf(out, "| Unique visitors | %d\n", info.UniqueVisitors)
+	fmt.Fprintf(out, "| Unique resources | %d\n", info.UniqueURLs)
+	fmt.Fprint(out, "|===\n\n")
+
+	if len(info.Formats) != 0 {
+		fmt.Fprint(out, "==== Log Formats\n\n")
+		fmt.Fprint(out, "[options=\"header\"]\n")
+		fmt.Fprint(out, "|===\n")
+		fmt.Fprint(out, "| File | Format\n")
+
+		for _, format := range info.Formats {
+			fmt.Fprintf(out, "| %s | %s\n", format.Path, format.Format)
+		}
+
+		fmt.Fprint(out, "|===\n\n")
+	}
+
+	if len(info.RequestsPerDay) != 0 {
+		fmt.Fprint(out, "==== Requests Per Day\n\n")
+		fmt.Fprint(out, "[options=\"header\"]\n")
+		fmt.Fprint(out, "|===\n")
+		fmt.Fprint(out, "| Day | Count | Unique addresses\n")
+
+		for _, day := range info.RequestsPerDay {
+			fmt.Fprintf(out, "| %s | %d | %d\n", day.Day, day.Quantity, day.UniqueAddresses)
+		}
+
+		fmt.Fprint(out, "|===\n\n")
+	}
+
+	if series := info.TimeSeries; series != nil {
+		fmt.Fprintf(out, "==== Time Series (%s)\n\n", series.Bucket)
+		fmt.Fprint(out, "[options=\"header\"]\n")
+		fmt.Fprint(out, "|===\n")
+		fmt.Fprint(out, "| Start | Count | Bytes | Error rate | Unique addresses\n")
+
+		for _, bucket := range series.Buckets {
+			fmt.Fprintf(out, "| %s | %d | %d | %s | %d\n",
+				bucket.Start, bucket.Quantity, bucket.Bytes, percent(bucket.Errors, bucket.Quantity), bucket.UniqueAddresses)
+		}
+
+		for _, summary := range timeSeriesSummary(series) {
+			fmt.Fprintf(out, "| *%s*: %s | %d | %d | %s | %d\n", summary.name,
+				summary.bucket.Start, summary.bucket.Quantity, summary.bucket.Bytes, percent(summary.bucket.Errors, summary.bucket.Quantity),
+				summary.bucket.UniqueAddresses)
+		}
+
+		fmt.Fprint(out, "|===\n\n")
+	}
+
+	if len(info.ResponseSizeQuantiles) != 0 {
+		fmt.Fprint(out, "==== Response Size Quantiles\n\n")
+		fmt.Fprint(out, "[options=\"header\"]\n")
+		fmt.Fprint(out, "|===\n")
+		fmt.Fprint(out, "| Quantile | Size\n")
+
+		for _, quantile := range info.ResponseSizeQuantiles {
+			fmt.Fprintf(out, "| %s | %d\n", quantileName(quantile.Quantile), quantile.Value)
+		}
+
+		fmt.Fprintf(out, "| max | %d\n", info.ResponseSizeMax)
+		fmt.Fprint(out, "|===\n\n")
+	}
+
+	header := ""
+	if metric := metricHeader(info); metric != "" {
+		header = " | " + metric
+	}
+
+	cell := func(bytes, errors int) string {
+		if header == "" {
+			return ""
+		}
+
+		return fmt.Sprintf(" | %d", metricValue(info, bytes, errors))
+	}
+
+	heavyHeader := ""
+	if info.HeavyHitters != nil {
+		heavyHeader = " | Max error"
+	}
+
+	heavyCell := func(maxError int) string {
+		if heavyHeader == "" {
+			return ""
+		}
+
+		return fmt.Sprintf(" | %d", maxError)
+	}
+
+	fmt.Fprint(out, "==== Requested Resources\n\n")
+	fmt.Fprint(out, "[options=\"header\"]\n")
+	fmt.Fprint(out, "|===\n")
+	fmt.Fprintf(out, "| Resource | Count%s%s\n", header, heavyHeader)
+
+	for _, url := range info.FrequentURLs {
+		fmt.Fprintf(out, "| `%s` | %d%s%s\n",
+			url.Name, url.Quantity, cell(url.Bytes, url.Errors), heavyCell(url.MaxError))
+	}
+
+	fmt.Fprint(out, "|===\n\n")
+
+	if info.HeavyHitters != nil {
+		fmt.Fprintf(out, "%s\n\n", heavyHittersNote(info, "resources", info.HeavyHitters.URLBound))
+	}
+
+	fmt.Fprint(out, "==== Response Codes\n\n")
+	fmt.Fprint(out, "[options=\"header\"]\n")
+	fmt.Fprint(out, "|===\n")
+	fmt.Fprintf(out, "| Code | Name | Count%s\n", header)
+
+	for _, status := range info.FrequentStatuses {
+		fmt.Fprintf(out, "| %s | %s | %d%s\n",
+			statusCode(&status), status.Name, status.Quantity, cell(status.Bytes, status.Errors))
+	}
+
+	fmt.Fprint(out, "|===\n\n")
+
+	fmt.Fprint(out, "==== Requesting addresses\n\n")
+	fmt.Fprint(out, "[options=\"header\"]\n")
+	fmt.Fprint(out, "|===\n")
+	fmt.Fprintf(out, "| Name | Count%s%s\n", header, heavyHeader)
+
+	for _, address := range info.FrequentAddresses {
+		fmt.Fprintf(out, "| %s | %d%s%s\n",
+			address.Name, address.Quantity, cell(address.Bytes, address.Errors), heavyCell(address.MaxError))
+	}
+
+	fmt.Fprint(out, "|===\n")
+
+	if info.HeavyHitters != nil {
+		fmt.Fprintf(out, "\n%s\n", heavyHittersNote(info, "addresses", info.HeavyHitters.AddressBound))
+	}
+
+	if len(info.Malformed) != 0 {
+		fmt.Fprint(out, "\n==== Malformed Lines\n\n")
+		fmt.Fprint(out, "[options=\"header\"]\n")
+		fmt.Fprint(out, "|===\n")
+		fmt.Fprint(out, "| File | Error | Count | Example lines\n")
+
+		for _, malformed := range info.Malformed {
+			fmt.Fprintf(out, "| %s | %s | %d | %s\n",
+				malformed.Path, malformed.Type, malformed.Count, joinInts(malformed.Examples))
+		}
+
+		fmt.Fprint(out, "|===\n")
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package render_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdoc(t *testing.T) {
+	tt := []struct {
+		name     string
+		info     *domain.FileInfo
+		expected string
+	}{
+		{
+			name: "Single file",
+			info: &domain.FileInfo{
+				Paths:             []string{"/var/log/nginx/access.log"},
+				TotalRequests:     100,
+				AvgResponseSize:   512,
+				ResponseSize95p:   800,
+				AvgResponsePerDay: 10,
+				FrequentURLs: []domain.URL{
+					domain.NewURL("/index.html", 50),
+					domain.NewURL("/about.html", 20),
+				},
+				FrequentStatuses: []domain.Status{
+					domain.NewStatus(200, 80),
+					domain.NewStatus(404, 10),
+				},
+				FrequentAddresses: []domain.Address{
+					domain.NewAddress("192.168.1.1", 30),
+					domain.NewAddress("10.0.0.2", 20),
+				},
+			},
+			expected: "==== General Information\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Метрика | Значение\n" +
+				"| Files | /var/log/nginx/access.log\n" +
+				"| Number of requests | 100\n" +
+				"| Average response size | 512\n" +
+				"| 95th percentile of response size | 800\n" +
+				"| Average requests per day | 10 |\n" +
+				"| Unique addresses | 0\n" +
+				"| Unique visitors | 0\n" +
+				"| Unique resources | 0\n" +
+				"|===\n\n" +
+
+				"==== Requested Resources\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Resource | Count\n" +
+				"| `/index.html` | 50\n" +
+				"| `/about.html` | 20\n" +
+				"|===\n\n" +
+
+				"==== Response Codes\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Code | Name | Count\n" +
+				"| 200 | OK | 80\n" +
+				"| 404 | Not Found | 10\n" +
+				"|===\n\n" +
+
+				"==== Requesting addresses\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Name | Count\n" +
+				"| 192.168.1.1 | 30\n" +
+				"| 10.0.0.2 | 20\n" +
+				"|===\n",
+		},
+		{
+			name: "Multiple files",
+			info: &domain.FileInfo{
+				Paths:             []string{"/var/log/nginx/access.log", "/var/log/nginx/access.log.1"},
+				TotalRequests:     1000,
+				AvgResponseSize:   1024,
+				ResponseSize95p:   1500,
+				AvgResponsePerDay: 100,
+				FrequentURLs: []domain.URL{
+					domain.NewURL("/home", 300),
+					domain.NewURL("/login", 150),
+					domain.NewURL("/dashboard", 100),
+				},
+				FrequentStatuses: []domain.Status{
+					domain.NewStatus(200, 700),
+					domain.NewStatus(403, 50),
+					domain.NewStatus(500, 20),
+				},
+				FrequentAddresses: []domain.Address{
+					domain.NewAddress("172.16.0.1", 200),
+					domain.NewAddress("192.168.1.2", 150),
+					domain.NewAddress("10.0.0.3", 120),
+				},
+			},
+			expected: "==== General Information\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Метрика | Значение\n" +
+				"| Files | /var/log/nginx/access.log, /var/log/nginx/access.log.1\n" +
+				"| Number of requests | 1000\n" +
+				"| Average response size | 1024\n" +
+				"| 95th percentile of response size | 1500\n" +
+				"| Average requests per day | 100 |\n" +
+				"| Unique addresses | 0\n" +
+				"| Unique visitors | 0\n" +
+				"| Unique resources | 0\n" +
+				"|===\n\n" +
+				"==== Requested Resources\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Resource | Count\n" +
+				"| `/home` | 300\n" +
+				"| `/login` | 150\n" +
+				"| `/dashboard` | 100\n" +
+				"|===\n\n" +
+				"==== Response Codes\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Code | Name | Count\n" +
+				"| 200 | OK | 700\n" +
+				"| 403 | Forbidden | 50\n" +
+				"| 500 | Internal Server Error | 20\n" +
+				"|===\n\n" +
+				"==== Requesting addresses\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Name | Count\n" +
+				"| 172.16.0.1 | 200\n" +
+				"| 192.168.1.2 | 150\n" +
+				"| 10.0.0.3 | 120\n" +
+				"|===\n",
+		},
+		{
+			name: "URL",
+			info: &domain.FileInfo{
+				Paths:             []string{"https://raw.githubusercontent.com/elastic/examples/master/Common%20Data%20Formats/nginx_logs/nginx_logs"},
+				TotalRequests:     5000,
+				AvgResponseSize:   2048,
+				ResponseSize95p:   3000,
+				AvgResponsePerDay: 500,
+				FrequentURLs: []domain.URL{
+					domain.NewURL("/home", 1000),
+					domain.NewURL("/products", 800),
+					domain.NewURL("/contact", 600),
+				},
+				FrequentStatuses: []domain.Status{
+					domain.NewStatus(200, 4000),
+					domain.NewStatus(404, 400),
+					domain.NewStatus(503, 50),
+				},
+				FrequentAddresses: []domain.Address{
+					domain.NewAddress("192.168.0.10", 500),
+					domain.NewAddress("192.168.0.20", 450),
+					domain.NewAddress("192.168.0.30", 300),
+				},
+			},
+			expected: "==== General Information\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Метрика | Значение\n" +
+				"| Files | https://raw.githubusercontent.com/elastic/examples/master/Common%20Data%20Formats/nginx_logs/nginx_logs\n" +
+				"| Number of requests | 5000\n" +
+				"| Average response size | 2048\n" +
+				"| 95th percentile of response size | 3000\n" +
+				"| Average requests per day | 500 |\n" +
+				"| Unique addresses | 0\n" +
+				"| Unique visitors | 0\n" +
+				"| Unique resources | 0\n" +
+				"|===\n\n" +
+				"==== Requested Resources\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Resource | Count\n" +
+				"| `/home` | 1000\n" +
+				"| `/products` | 800\n" +
+				"| `/contact` | 600\n" +
+				"|===\n\n" +
+				"==== Response Codes\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Code | Name | Count\n" +
+				"| 200 | OK | 4000\n" +
+				"| 404 | Not Found | 400\n" +
+				"| 503 | Service Unavailable | 50\n" +
+				"|===\n\n" +
+				"==== Requesting addresses\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Name | Count\n" +
+				"| 192.168.0.10 | 500\n" +
+				"| 192.168.0.20 | 450\n" +
+				"| 192.168.0.30 | 300\n" +
+				"|===\n",
+		},
+		{
+			name: "Time series",
+			info: &domain.FileInfo{
+				Paths:         []string{"access.log"},
+				TotalRequests: 3,
+				TimeSeries: domain.NewTimeSeries(
+					"hour",
+					[]domain.TimeBucket{
+						domain.NewTimeBucket("2024-10-22 09:00", 2, 300, 1, 2),
+						domain.NewTimeBucket("2024-10-22 10:00", 0, 0, 0, 0),
+						domain.NewTimeBucket("2024-10-22 11:00", 1, 100, 0, 1),
+					},
+					domain.NewTimeBucket("2024-10-22 09:00", 2, 300, 1, 2),
+					domain.NewTimeBucket("2024-10-22 10:00", 0, 0, 0, 0),
+				),
+			},
+			expected: "==== General Information\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Метрика | Значение\n" +
+				"| Files | access.log\n" +
+				"| Number of requests | 3\n" +
+				"| Average response size | 0\n" +
+				"| 95th percentile of response size | 0\n" +
+				"| Average requests per day | 0 |\n" +
+				"| Unique addresses | 0\n" +
+				"| Unique visitors | 0\n" +
+				"| Unique resources | 0\n" +
+				"|===\n\n" +
+				"==== Time Series (hour)\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Start | Count | Bytes | Error rate | Unique addresses\n" +
+				"| 2024-10-22 09:00 | 2 | 300 | 50.0% | 2\n" +
+				"| 2024-10-22 10:00 | 0 | 0 | 0.0% | 0\n" +
+				"| 2024-10-22 11:00 | 1 | 100 | 0.0% | 1\n" +
+				"| *Peak*: 2024-10-22 09:00 | 2 | 300 | 50.0% | 2\n" +
+				"| *Quietest*: 2024-10-22 10:00 | 0 | 0 | 0.0% | 0\n" +
+				"|===\n\n" +
+				"==== Requested Resources\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Resource | Count\n" +
+				"|===\n\n" +
+				"==== Response Codes\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Code | Name | Count\n" +
+				"|===\n\n" +
+				"==== Requesting addresses\n\n" +
+				"[options=\"header\"]\n" +
+				"|===\n" +
+				"| Name | Count\n" +
+				"|===\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, render.Adoc(tc.info, buf), "report must be rendered")
+
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
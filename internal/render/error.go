@@ -0,0 +1,15 @@
+package render
+
+type ErrTemplate struct {
+	msg string
+}
+
+func NewErrTemplate(msg string) error {
+	return ErrTemplate{
+		msg: msg,
+	}
+}
+
+func (e ErrTemplate) Error() string {
+	return e.msg
+}
@@ -0,0 +1,233 @@
+package render
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+)
+
+const (
+	chartWidth  = 720
+	chartHeight = 240
+	chartMargin = 40
+	pieRadius   = 100
+)
+
+var classColors = map[string]string{
+	"1xx": "#8e9aaf",
+	"2xx": "#4caf50",
+	"3xx": "#2196f3",
+	"4xx": "#ff9800",
+	"5xx": "#f44336",
+}
+
+//go:embed report.html
+var htmlReportTemplate string
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"quantileName": quantileName,
+}).Parse(htmlReportTemplate))
+
+type htmlBar struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+	Center float64
+	Label  string
+	Title  string
+}
+
+type htmlSlice struct {
+	Path    string
+	Circle  bool
+	Color   string
+	Class   string
+	Count   int
+	Percent string
+}
+
+type htmlChart struct {
+	Width  int
+	Height int
+	Base   int
+	Max    int
+	Bars   []htmlBar
+}
+
+type htmlView struct {
+	Info          *domain.FileInfo
+	Metric        string
+	Days          htmlChart
+	Sizes         htmlChart
+	Classes       []htmlSlice
+	PieRadius     int
+	PieCenter     int
+	PieSize       int
+	ShowDayLabels bool
+	URLsNote      string
+	AddressesNote string
+}
+
+func humanBytes(size int) string {
+	const unit = 1024
+
+	if size < unit {
+		return strconv.Itoa(size) + " B"
+	}
+
+	value := float64(size)
+	prefixes := "KMGTPE"
+
+	i := -1
+	for value >= unit && i < len(prefixes)-1 {
+		value /= unit
+		i++
+	}
+
+	return fmt.Sprintf("%.4g %ciB", value, prefixes[i])
+}
+
+func barChart(labels, titles []string, values []int) htmlChart {
+	chart := htmlChart{
+		Width:  chartWidth,
+		Height: chartHeight + chartMargin,
+		Base:   chartHeight,
+		Bars:   make([]htmlBar, len(values)),
+	}
+
+	for _, value := range values {
+		chart.Max = max(chart.Max, value)
+	}
+
+	if len(values) == 0 || chart.Max == 0 {
+		return chart
+	}
+
+	step := float64(chartWidth) / float64(len(values))
+	width := math.Max(step*0.8, 1)
+
+	for i, value := range values {
+		height := float64(chartHeight-chartMargin) * float64(value) / float64(chart.Max)
+
+		chart.Bars[i] = htmlBar{
+			X:      float64(i)*step + (step-width)/2,
+			Y:      float64(chartHeight) - height,
+			Width:  width,
+			Height: height,
+			Center: float64(i)*step + step/2,
+			Label:  labels[i],
+			Title:  titles[i],
+		}
+	}
+
+	return chart
+}
+
+func daysChart(days []domain.DayRequests) htmlChart {
+	labels := make([]string, len(days))
+	titles := make([]string, len(days))
+	values := make([]int, len(days))
+
+	for i, day := range days {
+		labels[i] = day.Day
+		titles[i] = fmt.Sprintf("%s: %d requests, %d unique addresses", day.Day, day.Quantity, day.UniqueAddresses)
+		values[i] = day.Quantity
+	}
+
+	return barChart(labels, titles, values)
+}
+
+func sizesChart(buckets []domain.SizeBucket) htmlChart {
+	labels := make([]string, len(buckets))
+	titles := make([]string, len(buckets))
+	values := make([]int, len(buckets))
+
+	for i, bucket := range buckets {
+		labels[i] = "< " + humanBytes(bucket.To)
+		titles[i] = fmt.Sprintf("%s to %s: %d responses", humanBytes(bucket.From), humanBytes(bucket.To), bucket.Quantity)
+		values[i] = bucket.Quantity
+	}
+
+	return barChart(labels, titles, values)
+}
+
+func pieSlices(classes []domain.StatusClass) []htmlSlice {
+	total := 0
+	for _, class := range classes {
+		total += class.Quantity
+	}
+
+	slices := make([]htmlSlice, 0, len(classes))
+	if total == 0 {
+		return slices
+	}
+
+	point := func(angle float64) (float64, float64) {
+		return pieRadius + pieRadius*math.Sin(angle), pieRadius - pieRadius*math.Cos(angle)
+	}
+
+	angle := 0.0
+
+	for _, class := range classes {
+		share := float64(class.Quantity) / float64(total)
+		slice := htmlSlice{
+			Color:   classColors[class.Class],
+			Class:   class.Class,
+			Count:   class.Quantity,
+			Percent: fmt.Sprintf("%.1f%%", 100*share),
+		}
+
+		if class.Quantity == total {
+			slice.Circle = true
+			slices = append(slices, slice)
+
+			continue
+		}
+
+		largeArc := 0
+		if share > 0.5 {
+			largeArc = 1
+		}
+
+		x1, y1 := point(angle)
+		angle += 2 * math.Pi * share
+		x2, y2 := point(angle)
+
+		slice.Path = fmt.Sprintf("M %d %d L %.2f %.2f A %d %d 0 %d 1 %.2f %.2f Z",
+			pieRadius, pieRadius, x1, y1, pieRadius, pieRadius, largeArc, x2, y2)
+		slices = append(slices, slice)
+	}
+
+	return slices
+}
+
+func HTML(info *domain.FileInfo, out io.Writer) error {
+	view := htmlView{
+		Info:          info,
+		Metric:        metricHeader(info),
+		Days:          daysChart(info.RequestsPerDay),
+		Sizes:         sizesChart(info.SizeHistogram),
+		Classes:       pieSlices(info.StatusClasses),
+		PieRadius:     pieRadius,
+		PieCenter:     pieRadius,
+		PieSize:       2 * pieRadius,
+		ShowDayLabels: len(info.RequestsPerDay) <= 31,
+	}
+
+	if info.HeavyHitters != nil {
+		view.URLsNote = heavyHittersNote(info, "resources", info.HeavyHitters.URLBound)
+		view.AddressesNote = heavyHittersNote(info, "addresses", info.HeavyHitters.AddressBound)
+	}
+
+	if err := htmlReport.Execute(out, view); err != nil {
+		return fmt.Errorf("render html report: %w", err)
+	}
+
+	return nil
+}
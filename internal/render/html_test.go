@@ -0,0 +1,85 @@
+package render_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTML(t *testing.T) {
+	tt := []struct {
+		name     string
+		info     *domain.FileInfo
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "full report",
+			info: &domain.FileInfo{
+				Paths:             []string{"logs/access.log"},
+				TotalRequests:     3,
+				FrequentURLs:      []domain.URL{domain.NewURL("/<script>alert(1)</script>", 3)},
+				FrequentStatuses:  []domain.Status{domain.NewStatus(200, 2), domain.NewStatus(404, 1)},
+				FrequentAddresses: []domain.Address{domain.NewAddress("10.0.0.1", 3)},
+				RequestsPerDay: []domain.DayRequests{
+					domain.NewDayRequests("2024-10-22", 1),
+					{Day: "2024-10-23", Quantity: 2, UniqueAddresses: 1},
+				},
+				UniqueAddresses: 1,
+				StatusClasses:   []domain.StatusClass{domain.NewStatusClass("2xx", 2), domain.NewStatusClass("4xx", 1)},
+				SizeHistogram:   []domain.SizeBucket{domain.NewSizeBucket(512, 1024, 1), domain.NewSizeBucket(1024, 2048, 2)},
+			},
+			contains: []string{
+				`aria-label="Requests per day"`,
+				`<title>2024-10-23: 2 requests, 1 unique addresses</title>`,
+				`<tr><td>2024-10-23</td><td class="num">2</td><td class="num">1</td></tr>`,
+				`<tr><td>Unique addresses</td><td class="num">1</td></tr>`,
+				`<path d="M 100 100 L 100.00 0.00 A 100 100 0 1 1`,
+				`<td class="num">66.7%</td>`,
+				`<title>1 KiB to 2 KiB: 2 responses</title>`,
+				`&lt;script&gt;alert(1)&lt;/script&gt;`,
+				`<td>Not Found</td>`,
+				`table.sortable`,
+			},
+			excludes: []string{
+				"<script>alert(1)",
+				"No requests.",
+			},
+		},
+		{
+			name: "single status class",
+			info: &domain.FileInfo{
+				StatusClasses: []domain.StatusClass{domain.NewStatusClass("5xx", 7)},
+			},
+			contains: []string{
+				`<circle cx="100" cy="100" r="100" fill="#f44336"><title>5xx: 7</title></circle>`,
+				`No requests.`,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, render.HTML(tc.info, buf), "report must be rendered")
+
+			report := buf.String()
+
+			for _, expected := range tc.contains {
+				assert.Contains(t, report, expected)
+			}
+
+			for _, unexpected := range tc.excludes {
+				assert.NotContains(t, report, unexpected)
+			}
+
+			assert.False(t, strings.Contains(report, "http://") || strings.Contains(report, "https://"),
+				"report must not load external resources")
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+)
+
+const JSONSchemaVersion = 2
+
+type jsonURL struct {
+	domain.URL
+	Bytes  *int `json:"bytes,omitempty"`
+	Errors *int `json:"errors,omitempty"`
+}
+
+type jsonStatus struct {
+	domain.Status
+	Bytes  *int `json:"bytes,omitempty"`
+	Errors *int `json:"errors,omitempty"`
+}
+
+type jsonAddress struct {
+	domain.Address
+	Bytes  *int `json:"bytes,omitempty"`
+	Errors *int `json:"errors,omitempty"`
+}
+
+type jsonReport struct {
+	SchemaVersion int `json:"schema_version"`
+	domain.FileInfo
+	FrequentURLs      []jsonURL     `json:"frequent_urls"`
+	FrequentStatuses  []jsonStatus  `json:"frequent_statuses"`
+	FrequentAddresses []jsonAddress `json:"frequent_addresses"`
+}
+
+func nonNil[T any](sl []T) []T {
+	if sl == nil {
+		return []T{}
+	}
+
+	return sl
+}
+
+func metricValues(metric string, bytes, errors int) (*int, *int) {
+	switch metric {
+	case "bytes":
+		return &bytes, nil
+
+	case "errors":
+		return nil, &errors
+
+	default:
+		return nil, nil
+	}
+}
+
+func newJSONReport(info *domain.FileInfo) jsonReport {
+	report := jsonReport{
+		SchemaVersion:     JSONSchemaVersion,
+		FileInfo:          *info,
+		FrequentURLs:      make([]jsonURL, len(info.FrequentURLs)),
+		FrequentStatuses:  make([]jsonStatus, len(info.FrequentStatuses)),
+		FrequentAddresses: make([]jsonAddress, len(info.FrequentAddresses)),
+	}
+
+	for i, url := range info.FrequentURLs {
+		byteCount, errorCount := metricValues(info.TopMetric, url.Bytes, url.Errors)
+		report.FrequentURLs[i] = jsonURL{URL: url, Bytes: byteCount, Errors: errorCount}
+	}
+
+	for i, status := range info.FrequentStatuses {
+		byteCount, errorCount := metricValues(info.TopMetric, status.Bytes, status.Errors)
+		report.FrequentStatuses[i] = jsonStatus{Status: status, Bytes: byteCount, Errors: errorCount}
+	}
+
+	for i, address := range info.FrequentAddresses {
+		byteCount, errorCount := metricValues(info.TopMetric, address.Bytes, address.Errors)
+		report.FrequentAddresses[i] = jsonAddress{Address: address, Bytes: byteCount, Errors: errorCount}
+	}
+
+	report.Paths = nonNil(report.Paths)
+	report.Formats = nonNil(report.Formats)
+	report.Malformed = nonNil(report.Malformed)
+	report.RequestsPerDay = nonNil(report.RequestsPerDay)
+	report.StatusClasses = nonNil(report.StatusClasses)
+	report.SizeHistogram = nonNil(report.SizeHistogram)
+	report.Statuses = nonNil(report.Statuses)
+	report.Methods = nonNil(report.Methods)
+	report.ResponseSizeQuantiles = nonNil(report.ResponseSizeQuantiles)
+
+	return report
+}
+
+func JSON(info *domain.FileInfo, out io.Writer) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(newJSONReport(info)); err != nil {
+		return fmt.Errorf("encode json report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package render_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonSchema struct {
+	Ref        string                `json:"$ref"`
+	Defs       map[string]jsonSchema `json:"$defs"`
+	Required   []string              `json:"required"`
+	Properties map[string]jsonSchema `json:"properties"`
+	Items      *jsonSchema           `json:"items"`
+	Minimum    *float64              `json:"minimum"`
+}
+
+func checkSchema(t *testing.T, path string, root, schema *jsonSchema, value any) {
+	t.Helper()
+
+	if name, ok := strings.CutPrefix(schema.Ref, "#/$defs/"); ok {
+		def, found := root.Defs[name]
+		require.True(t, found, "definition %s of %s must be in the schema", name, path)
+
+		schema = &def
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			property, ok := schema.Properties[key]
+			require.True(t, ok, "field %s.%s must be described in the schema", path, key)
+
+			checkSchema(t, path+"."+key, root, &property, value[key])
+		}
+
+		for _, key := range schema.Required {
+			assert.Contains(t, value, key, "required field %s.%s must be present", path, key)
+		}
+
+	case float64:
+		if schema.Minimum != nil {
+			assert.GreaterOrEqual(t, value, *schema.Minimum, "field %s must not be less than the schema minimum", path)
+		}
+
+	case []any:
+		require.NotNil(t, schema.Items, "array %s must describe its items", path)
+
+		for _, item := range value {
+			checkSchema(t, path+"[]", root, schema.Items, item)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tt := []struct {
+		name string
+		info *domain.FileInfo
+	}{
+		{
+			name: "full report",
+			info: &domain.FileInfo{
+				Paths:             []string{"logs/access.log"},
+				TotalRequests:     3,
+				AvgResponseSize:   100,
+				ResponseSize95p:   200,
+				AvgResponsePerDay: 3,
+				FrequentURLs:      []domain.URL{domain.NewURL("/a", 2), domain.NewURL("/b", 1)},
+				FrequentStatuses:  []domain.Status{domain.NewStatus(200, 2), domain.NewStatus(499, 1)},
+				FrequentAddresses: []domain.Address{domain.NewAddress("10.0.0.1", 3)},
+				Formats:           []domain.SourceFormat{domain.NewSourceFormat("logs/access.log", "combined")},
+				TotalLines:        4,
+				Malformed:         []domain.Malformed{domain.NewMalformed("logs/access.log", "regexp", 1, []int{2})},
+				RequestsPerDay:    []domain.DayRequests{{Day: "2024-10-22", Quantity: 3, UniqueAddresses: 1}},
+				StatusClasses:     []domain.StatusClass{domain.NewStatusClass("2xx", 2), domain.NewStatusClass("4xx", 1)},
+				SizeHistogram:     []domain.SizeBucket{domain.NewSizeBucket(64, 128, 1), domain.NewSizeBucket(128, 256, 2)},
+				ResponseSizeSum:   300,
+				Statuses:          []domain.Status{domain.NewStatus(200, 2), domain.NewStatus(499, 1)},
+				Methods:           []domain.Method{domain.NewMethod("GET", 3)},
+				ResponseSizeQuantiles: []domain.Quantile{
+					domain.NewQuantile(0.5, 100),
+					domain.NewQuantile(0.999, 200),
+				},
+				ResponseSizeMax: 200,
+				UniqueAddresses: 1,
+				UniqueVisitors:  1,
+				UniqueURLs:      2,
+			},
+		},
+		{
+			name: "approximate report",
+			info: &domain.FileInfo{
+				Paths:             []string{"-"},
+				TotalRequests:     10,
+				FrequentURLs:      []domain.URL{{Name: "/a", Quantity: 7, MaxError: 1}},
+				FrequentAddresses: []domain.Address{{Name: "10.0.0.1", Quantity: 5, MaxError: 2}},
+				HeavyHitters:      domain.NewHeavyHitters(2, 1, 2),
+			},
+		},
+		{
+			name: "other row",
+			info: &domain.FileInfo{
+				Paths:            []string{"access.log"},
+				TotalRequests:    5,
+				FrequentStatuses: []domain.Status{domain.NewStatus(200, 3), domain.NewOtherStatus(2)},
+			},
+		},
+		{
+			name: "time series",
+			info: &domain.FileInfo{
+				Paths:         []string{"access.log"},
+				TotalRequests: 3,
+				TimeZone:      "Europe/Moscow",
+				TimeSeries: domain.NewTimeSeries(
+					"hour",
+					[]domain.TimeBucket{
+						domain.NewTimeBucket("2024-10-22 09:00", 2, 300, 1, 2),
+						domain.NewTimeBucket("2024-10-22 10:00", 0, 0, 0, 0),
+						domain.NewTimeBucket("2024-10-22 11:00", 1, 100, 0, 1),
+					},
+					domain.NewTimeBucket("2024-10-22 09:00", 2, 300, 1, 2),
+					domain.NewTimeBucket("2024-10-22 10:00", 0, 0, 0, 0),
+				),
+			},
+		},
+		{
+			name: "empty report",
+			info: &domain.FileInfo{},
+		},
+	}
+
+	rawSchema, err := os.ReadFile("../../schema/report.schema.json")
+	require.NoError(t, err, "schema must be read")
+
+	var schema jsonSchema
+
+	require.NoError(t, json.Unmarshal(rawSchema, &schema), "schema must be valid json")
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, render.JSON(tc.info, buf), "report must be rendered")
+
+			var report map[string]any
+
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &report), "report must be valid json")
+
+			assert.InDelta(t, render.JSONSchemaVersion, report["schema_version"], 0)
+			checkSchema(t, "report", &schema, &schema, report)
+		})
+	}
+}
+
+func TestJSONFieldNames(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := render.JSON(&domain.FileInfo{
+		Paths:            []string{"-"},
+		TotalRequests:    1,
+		FrequentStatuses: []domain.Status{domain.NewStatus(404, 1)},
+	}, buf)
+	require.NoError(t, err, "report must be rendered")
+
+	assert.JSONEq(t, `{
+		"schema_version": 2,
+		"paths": ["-"],
+		"total_requests": 1,
+		"avg_response_size": 0,
+		"response_size_95p": 0,
+		"avg_requests_per_day": 0,
+		"frequent_urls": [],
+		"frequent_statuses": [{"code": 404, "name": "Not Found", "count": 1}],
+		"frequent_addresses": [],
+		"formats": [],
+		"total_lines": 0,
+		"malformed": [],
+		"requests_per_day": [],
+		"status_classes": [],
+		"response_size_histogram": [],
+		"response_size_sum": 0,
+		"statuses": [],
+		"methods": [],
+		"response_size_quantiles": [],
+		"response_size_max": 0,
+		"unique_addresses": 0,
+		"unique_visitors": 0,
+		"unique_urls": 0
+	}`, buf.String())
+}
+
+func TestJSONTopMetric(t *testing.T) {
+	tt := []struct {
+		name   string
+		metric string
+		want   string
+	}{
+		{
+			name:   "count",
+			metric: "count",
+			want:   `[{"address": "10.0.0.1", "count": 2}, {"address": "(other)", "count": 1}]`,
+		},
+		{
+			name:   "bytes",
+			metric: "bytes",
+			want:   `[{"address": "10.0.0.1", "count": 2, "bytes": 0}, {"address": "(other)", "count": 1, "bytes": 0}]`,
+		},
+		{
+			name:   "errors",
+			metric: "errors",
+			want:   `[{"address": "10.0.0.1", "count": 2, "errors": 0}, {"address": "(other)", "count": 1, "errors": 0}]`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := render.JSON(&domain.FileInfo{
+				TotalRequests:     3,
+				TopMetric:         tc.metric,
+				FrequentAddresses: []domain.Address{domain.NewAddress("10.0.0.1", 2), domain.NewAddress(domain.OtherName, 1)},
+			}, buf)
+			require.NoError(t, err, "report must be rendered")
+
+			var report struct {
+				FrequentAddresses json.RawMessage `json:"frequent_addresses"`
+			}
+
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &report), "report must be valid json")
+			assert.JSONEq(t, tc.want, string(report.FrequentAddresses))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+)
+
+func Markdown(info *domain.FileInfo, out io.Writer) error {
+	fmt.Fprint(out, "#### General information\n\n")
+	fmt.Fprint(out, "| Метрика | Значение |\n")
+	fmt.Fprint(out, "|:-|-:|\n")
+	fmt.Fprintf(out, "| Files | %s |\n", strings.Join(info.Paths, ", "))
+
+	if info.TimeZone != "" {
+		fmt.Fprintf(out, "| Time zone | %s |\n", info.TimeZone)
+	}
+
+	fmt.Fprintf(out, "| Number of requests | %d |\n", info.TotalRequests)
+	fmt.Fprintf(out, "| Average response size | %d |\n", info.AvgResponseSize)
+	fmt.Fprintf(out, "| 95th Percentile of response size | %d |\n", info.ResponseSize95p)
+	fmt.Fprintf(out, "| Average requests per day | %d |\n", info.AvgResponsePerDay)
+	fmt.Fprintf(out, "| Unique addresses | %d |\n", info.UniqueAddresses)
+	fmt.Fprintf(out, "| Unique visitors | %d |\n", info.UniqueVisitors)
+	fmt.Fprintf(out, "| Unique resources | %d |\n\n", info.UniqueURLs)
+
+	if len(info.Formats) != 0 {
+		fmt.Fprint(out, "#### Log formats\n\n")
+		fmt.Fprint(out, "| File | Format |\n")
+		fmt.Fprint(out, "|:-|:-|\n")
+
+		for _, format := range info.Formats {
+			fmt.Fprintf(out, "| %s | %s |\n", format.Path, format.Format)
+		}
+
+		fmt.Fprint(out, "\n")
+	}
+
+	if len(info.RequestsPerDay) != 0 {
+		fmt.Fprint(out, "#### Requests per day\n\n")
+		fmt.Fprint(out, "| Day | Count | Unique addresses |\n")
+		fmt.Fprint(out, "|:-|-:|-:|\n")
+
+		for _, day := range info.RequestsPerDay {
+			fmt.Fprintf(out, "| %s | %d | %d |\n", day.Day, day.Quantity, day.UniqueAddresses)
+		}
+
+		fmt.Fprint(out, "\n")
+	}
+
+	if series := info.TimeSeries; series != nil {
+		fmt.Fprintf(out, "#### Time series (%s)\n\n", series.Bucket)
+		fmt.Fprint(out, "| Start | Count | Bytes | Error rate | Unique addresses |\n")
+		fmt.Fprint(out, "|:-|-:|-:|-:|-:|\n")
+
+		for _, bucket := range series.Buckets {
+			fmt.Fprintf(out, "| %s | %d | %d | %s | %d |\n",
+				bucket.Start, bucket.Quantity, bucket.Bytes, percent(bucket.Errors, bucket.Quantity), bucket.UniqueAddresses)
+		}
+
+		for _, summary := range timeSeriesSummary(series) {
+			fmt.Fprintf(out, "| **%s**: %s | %d | %d | %s | %d |\n", summary.name,
+				summary.bucket.Start, summary.bucket.Quantity, summary.bucket.Bytes, percent(summary.bucket.Errors, summary.bucket.Quantity),
+				summary.bucket.UniqueAddresses)
+		}
+
+		fmt.Fprint(out, "\n")
+	}
+
+	if len(info.ResponseSizeQuantiles) != 0 {
+		fmt.Fprint(out, "#### Response size quantiles\n\n")
+		fmt.Fprint(out, "| Quantile | Size |\n")
+		fmt.Fprint(out, "|:-|-:|\n")
+
+		for _, quantile := range info.ResponseSizeQuantiles {
+			fmt.Fprintf(out, "| %s | %d |\n", quantileName(quantile.Quantile), quantile.Value)
+		}
+
+		fmt.Fprintf(out, "| max | %d |\n\n", info.ResponseSizeMax)
+	}
+
+	header, align := "", ""
+	if metric := metricHeader(info); metric != "" {
+		header, align = " "+metric+" |", "-:|"
+	}
+
+	cell := func(bytes, errors int) string {
+		if header == "" {
+			return ""
+		}
+
+		return fmt.Sprintf(" %d |", metricValue(info, bytes, errors))
+	}
+
+	heavyHeader, heavyAlign := "", ""
+	if info.HeavyHitters != nil {
+		heavyHeader, heavyAlign = " Max error |", "-:|"
+	}
+
+	heavyCell := func(maxError int) string {
+		if heavyHeader == "" {
+			return ""
+		}
+
+		return fmt.Sprintf(" %d |", maxError)
+	}
+
+	fmt.Fprint(out, "#### Requested resources\n\n")
+	fmt.Fprintf(out, "| Resource | Count |%s%s\n", header, heavyHeader)
+	fmt.Fprintf(out, "|:-|-:|%s%s\n", align, heavyAlign)
+
+	for _, url := range info.FrequentURLs {
+		fmt.Fprintf(out, "| `%s` | %d |%s%s\n",
+			url.Name, url.Quantity, cell(url.Bytes, url.Errors), heavyCell(url.MaxError))
+	}
+
+	if info.HeavyHitters != nil {
+		fmt.Fprintf(out, "\n%s\n", heavyHittersNote(info, "resources", info.HeavyHitters.URLBound))
+	}
+
+	fmt.Fprint(out, "\n#### Response codes\n\n")
+	fmt.Fprintf(out, "| Code | Name | Count |%s\n", header)
+	fmt.Fprintf(out, "|:-|:-:|-:|%s\n", align)
+
+	for _, status := range info.FrequentStatuses {
+		fmt.Fprintf(out, "| %s | %s | %d |%s\n",
+			statusCode(&status), status.Name, status.Quantity, cell(status.Bytes, status.Errors))
+	}
+
+	fmt.Fprint(out, "\n#### Requesting addresses\n\n")
+	fmt.Fprintf(out, "| Address | Count |%s%s\n", header, heavyHeader)
+	fmt.Fprintf(out, "|:-|-:|%s%s\n", align, heavyAlign)
+
+	for _, address := range info.FrequentAddresses {
+		fmt.Fprintf(out, "| `%s` | %d |%s%s\n",
+			address.Name, address.Quantity, cell(address.Bytes, address.Errors), heavyCell(address.MaxError))
+	}
+
+	if info.HeavyHitters != nil {
+		fmt.Fprintf(out, "\n%s\n", heavyHittersNote(info, "addresses", info.HeavyHitters.AddressBound))
+	}
+
+	if len(info.Malformed) != 0 {
+		fmt.Fprint(out, "\n#### Malformed lines\n\n")
+		fmt.Fprint(out, "| File | Error | Count | Example lines |\n")
+		fmt.Fprint(out, "|:-|:-:|-:|:-|\n")
+
+		for _, malformed := range info.Malformed {
+			fmt.Fprintf(out, "| %s | %s | %d | %s |\n",
+				malformed.Path, malformed.Type, malformed.Count, joinInts(malformed.Examples))
+		}
+	}
+
+	return nil
+}
+
+func joinInts(values []int) string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = strconv.Itoa(value)
+	}
+
+	return strings.Join(strs, ", ")
+}
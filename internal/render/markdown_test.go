@@ -0,0 +1,345 @@
+package render_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdown(t *testing.T) {
+	tt := []struct {
+		name     string
+		info     *domain.FileInfo
+		expected string
+	}{
+		{
+			name: "Single file",
+			info: &domain.FileInfo{
+				Paths:             []string{"/var/log/nginx/access.log"},
+				TotalRequests:     100,
+				AvgResponseSize:   512,
+				ResponseSize95p:   800,
+				AvgResponsePerDay: 10,
+				FrequentURLs: []domain.URL{
+					domain.NewURL("/index.html", 50),
+					domain.NewURL("/about.html", 20),
+				},
+				FrequentStatuses: []domain.Status{
+					domain.NewStatus(200, 80),
+					domain.NewStatus(404, 10),
+				},
+				FrequentAddresses: []domain.Address{
+					domain.NewAddress("192.168.1.1", 30),
+					domain.NewAddress("10.0.0.2", 20),
+				},
+			},
+			expected: "#### General information\n\n" +
+				"| Метрика | Значение |\n" +
+				"|:-|-:|\n" +
+				"| Files | /var/log/nginx/access.log |\n" +
+				"| Number of requests | 100 |\n" +
+				"| Average response size | 512 |\n" +
+				"| 95th Percentile of response size | 800 |\n" +
+				"| Average requests per day | 10 |\n" +
+				"| Unique addresses | 0 |\n" +
+				"| Unique visitors | 0 |\n" +
+				"| Unique resources | 0 |\n\n" +
+				"#### Requested resources\n\n" +
+				"| Resource | Count |\n" +
+				"|:-|-:|\n" +
+				"| `/index.html` | 50 |\n" +
+				"| `/about.html` | 20 |\n\n" +
+				"#### Response codes\n\n" +
+				"| Code | Name | Count |\n" +
+				"|:-|:-:|-:|\n" +
+				"| 200 | OK | 80 |\n" +
+				"| 404 | Not Found | 10 |\n\n" +
+				"#### Requesting addresses\n\n" +
+				"| Address | Count |\n" +
+				"|:-|-:|\n" +
+				"| `192.168.1.1` | 30 |\n" +
+				"| `10.0.0.2` | 20 |\n",
+		},
+		{
+			name: "Multiple files",
+			info: &domain.FileInfo{
+				Paths:             []string{"/var/log/nginx/access.log", "/var/log/nginx/access.log.1"},
+				TotalRequests:     1000,
+				AvgResponseSize:   1024,
+				ResponseSize95p:   1500,
+				AvgResponsePerDay: 100,
+				FrequentURLs: []domain.URL{
+					domain.NewURL("/home", 300),
+					domain.NewURL("/login", 150),
+					domain.NewURL("/dashboard", 100),
+				},
+				FrequentStatuses: []domain.Status{
+					domain.NewStatus(200, 700),
+					domain.NewStatus(403, 50),
+					domain.NewStatus(500, 20),
+				},
+				FrequentAddresses: []domain.Address{
+					domain.NewAddress("172.16.0.1", 200),
+					domain.NewAddress("192.168.1.2", 150),
+					domain.NewAddress("10.0.0.3", 120),
+				},
+			},
+			expected: "#### General information\n\n" +
+				"| Метрика | Значение |\n" +
+				"|:-|-:|\n" +
+				"| Files | /var/log/nginx/access.log, /var/log/nginx/access.log.1 |\n" +
+				"| Number of requests | 1000 |\n" +
+				"| Average response size | 1024 |\n" +
+				"| 95th Percentile of response size | 1500 |\n" +
+				"| Average requests per day | 100 |\n" +
+				"| Unique addresses | 0 |\n" +
+				"| Unique visitors | 0 |\n" +
+				"| Unique resources | 0 |\n\n" +
+				"#### Requested resources\n\n" +
+				"| Resource | Count |\n" +
+				"|:-|-:|\n" +
+				"| `/home` | 300 |\n" +
+				"| `/login` | 150 |\n" +
+				"| `/dashboard` | 100 |\n\n" +
+				"#### Response codes\n\n" +
+				"| Code | Name | Count |\n" +
+				"|:-|:-:|-:|\n" +
+				"| 200 | OK | 700 |\n" +
+				"| 403 | Forbidden | 50 |\n" +
+				"| 500 | Internal Server Error | 20 |\n\n" +
+				"#### Requesting addresses\n\n" +
+				"| Address | Count |\n" +
+				"|:-|-:|\n" +
+				"| `172.16.0.1` | 200 |\n" +
+				"| `192.168.1.2` | 150 |\n" +
+				"| `10.0.0.3` | 120 |\n",
+		},
+		{
+			name: "URL",
+			info: &domain.FileInfo{
+				Paths:             []string{"https://raw.githubusercontent.com/elastic/examples/master/Common%20Data%20Formats/nginx_logs/nginx_logs"},
+				TotalRequests:     5000,
+				AvgResponseSize:   2048,
+				ResponseSize95p:   3000,
+				AvgResponsePerDay: 500,
+				FrequentURLs: []domain.URL{
+					domain.NewURL("/home", 1000),
+					domain.NewURL("/products", 800),
+					domain.NewURL("/contact", 600),
+				},
+				FrequentStatuses: []domain.Status{
+					domain.NewStatus(200, 4000),
+					domain.NewStatus(404, 400),
+					domain.NewStatus(503, 50),
+				},
+				FrequentAddresses: []domain.Address{
+					domain.NewAddress("192.168.0.10", 500),
+					domain.NewAddress("192.168.0.20", 450),
+					domain.NewAddress("192.168.0.30", 300),
+				},
+			},
+			expected: "#### General information\n\n" +
+				"| Метрика | Значение |\n" +
+				"|:-|-:|\n" +
+				"| Files | https://raw.githubusercontent.com/elastic/examples/master/Common%20Data%20Formats/nginx_logs/nginx_logs |\n" +
+				"| Number of requests | 5000 |\n" +
+				"| Average response size | 2048 |\n" +
+				"| 95th Percentile of response size | 3000 |\n" +
+				"| Average requests per day | 500 |\n" +
+				"| Unique addresses | 0 |\n" +
+				"| Unique visitors | 0 |\n" +
+				"| Unique resources | 0 |\n\n" +
+				"#### Requested resources\n\n" +
+				"| Resource | Count |\n" +
+				"|:-|-:|\n" +
+				"| `/home` | 1000 |\n" +
+				"| `/products` | 800 |\n" +
+				"| `/contact` | 600 |\n\n" +
+				"#### Response codes\n\n" +
+				"| Code | Name | Count |\n" +
+				"|:-|:-:|-:|\n" +
+				"| 200 | OK | 4000 |\n" +
+				"| 404 | Not Found | 400 |\n" +
+				"| 503 | Service Unavailable | 50 |\n\n" +
+				"#### Requesting addresses\n\n" +
+				"| Address | Count |\n" +
+				"|:-|-:|\n" +
+				"| `192.168.0.10` | 500 |\n" +
+				"| `192.168.0.20` | 450 |\n" +
+				"| `192.168.0.30` | 300 |\n",
+		},
+		{
+			name: "Ranked by bytes with other row",
+			info: &domain.FileInfo{
+				Paths:             []string{"access.log"},
+				TotalRequests:     10,
+				AvgResponseSize:   100,
+				ResponseSize95p:   200,
+				AvgResponsePerDay: 10,
+				FrequentURLs: []domain.URL{
+					{Name: "/big", Quantity: 2, Bytes: 800},
+					{Name: domain.OtherName, Quantity: 8, Bytes: 200},
+				},
+				FrequentStatuses: []domain.Status{
+					{Code: 200, Name: "OK", Quantity: 9, Bytes: 900},
+					{Name: domain.OtherName, Quantity: 1, Bytes: 100},
+				},
+				FrequentAddresses: []domain.Address{
+					{Name: "10.0.0.1", Quantity: 10, Bytes: 1000},
+				},
+				TopMetric: "bytes",
+			},
+			expected: "#### General information\n\n" +
+				"| Метрика | Значение |\n" +
+				"|:-|-:|\n" +
+				"| Files | access.log |\n" +
+				"| Number of requests | 10 |\n" +
+				"| Average response size | 100 |\n" +
+				"| 95th Percentile of response size | 200 |\n" +
+				"| Average requests per day | 10 |\n" +
+				"| Unique addresses | 0 |\n" +
+				"| Unique visitors | 0 |\n" +
+				"| Unique resources | 0 |\n\n" +
+				"#### Requested resources\n\n" +
+				"| Resource | Count | Bytes |\n" +
+				"|:-|-:|-:|\n" +
+				"| `/big` | 2 | 800 |\n" +
+				"| `(other)` | 8 | 200 |\n\n" +
+				"#### Response codes\n\n" +
+				"| Code | Name | Count | Bytes |\n" +
+				"|:-|:-:|-:|-:|\n" +
+				"| 200 | OK | 9 | 900 |\n" +
+				"| - | (other) | 1 | 100 |\n\n" +
+				"#### Requesting addresses\n\n" +
+				"| Address | Count | Bytes |\n" +
+				"|:-|-:|-:|\n" +
+				"| `10.0.0.1` | 10 | 1000 |\n",
+		},
+		{
+			name: "Unique counts",
+			info: &domain.FileInfo{
+				Paths:             []string{"access.log"},
+				TotalRequests:     3,
+				AvgResponsePerDay: 1,
+				RequestsPerDay: []domain.DayRequests{
+					{Day: "2024-10-22", Quantity: 2, UniqueAddresses: 2},
+					{Day: "2024-10-23", Quantity: 1, UniqueAddresses: 1},
+				},
+				UniqueAddresses: 2,
+				UniqueVisitors:  3,
+				UniqueURLs:      1,
+			},
+			expected: "#### General information\n\n" +
+				"| Метрика | Значение |\n" +
+				"|:-|-:|\n" +
+				"| Files | access.log |\n" +
+				"| Number of requests | 3 |\n" +
+				"| Average response size | 0 |\n" +
+				"| 95th Percentile of response size | 0 |\n" +
+				"| Average requests per day | 1 |\n" +
+				"| Unique addresses | 2 |\n" +
+				"| Unique visitors | 3 |\n" +
+				"| Unique resources | 1 |\n\n" +
+				"#### Requests per day\n\n" +
+				"| Day | Count | Unique addresses |\n" +
+				"|:-|-:|-:|\n" +
+				"| 2024-10-22 | 2 | 2 |\n" +
+				"| 2024-10-23 | 1 | 1 |\n\n" +
+				"#### Requested resources\n\n" +
+				"| Resource | Count |\n" +
+				"|:-|-:|\n\n" +
+				"#### Response codes\n\n" +
+				"| Code | Name | Count |\n" +
+				"|:-|:-:|-:|\n\n" +
+				"#### Requesting addresses\n\n" +
+				"| Address | Count |\n" +
+				"|:-|-:|\n",
+		},
+		{
+			name: "Time series",
+			info: &domain.FileInfo{
+				Paths:         []string{"access.log"},
+				TotalRequests: 3,
+				TimeSeries: domain.NewTimeSeries(
+					"hour",
+					[]domain.TimeBucket{
+						domain.NewTimeBucket("2024-10-22 09:00", 2, 300, 1, 2),
+						domain.NewTimeBucket("2024-10-22 10:00", 0, 0, 0, 0),
+						domain.NewTimeBucket("2024-10-22 11:00", 1, 100, 0, 1),
+					},
+					domain.NewTimeBucket("2024-10-22 09:00", 2, 300, 1, 2),
+					domain.NewTimeBucket("2024-10-22 10:00", 0, 0, 0, 0),
+				),
+			},
+			expected: "#### General information\n\n" +
+				"| Метрика | Значение |\n" +
+				"|:-|-:|\n" +
+				"| Files | access.log |\n" +
+				"| Number of requests | 3 |\n" +
+				"| Average response size | 0 |\n" +
+				"| 95th Percentile of response size | 0 |\n" +
+				"| Average requests per day | 0 |\n" +
+				"| Unique addresses | 0 |\n" +
+				"| Unique visitors | 0 |\n" +
+				"| Unique resources | 0 |\n\n" +
+				"#### Time series (hour)\n\n" +
+				"| Start | Count | Bytes | Error rate | Unique addresses |\n" +
+				"|:-|-:|-:|-:|-:|\n" +
+				"| 2024-10-22 09:00 | 2 | 300 | 50.0% | 2 |\n" +
+				"| 2024-10-22 10:00 | 0 | 0 | 0.0% | 0 |\n" +
+				"| 2024-10-22 11:00 | 1 | 100 | 0.0% | 1 |\n" +
+				"| **Peak**: 2024-10-22 09:00 | 2 | 300 | 50.0% | 2 |\n" +
+				"| **Quietest**: 2024-10-22 10:00 | 0 | 0 | 0.0% | 0 |\n\n" +
+				"#### Requested resources\n\n" +
+				"| Resource | Count |\n" +
+				"|:-|-:|\n\n" +
+				"#### Response codes\n\n" +
+				"| Code | Name | Count |\n" +
+				"|:-|:-:|-:|\n\n" +
+				"#### Requesting addresses\n\n" +
+				"| Address | Count |\n" +
+				"|:-|-:|\n",
+		},
+		{
+			name: "Time zone",
+			info: &domain.FileInfo{
+				Paths:    []string{"access.log"},
+				TimeZone: "Europe/Moscow",
+			},
+			expected: "#### General information\n\n" +
+				"| Метрика | Значение |\n" +
+				"|:-|-:|\n" +
+				"| Files | access.log |\n" +
+				"| Time zone | Europe/Moscow |\n" +
+				"| Number of requests | 0 |\n" +
+				"| Average response size | 0 |\n" +
+				"| 95th Percentile of response size | 0 |\n" +
+				"| Average requests per day | 0 |\n" +
+				"| Unique addresses | 0 |\n" +
+				"| Unique visitors | 0 |\n" +
+				"| Unique resources | 0 |\n\n" +
+				"#### Requested resources\n\n" +
+				"| Resource | Count |\n" +
+				"|:-|-:|\n\n" +
+				"#### Response codes\n\n" +
+				"| Code | Name | Count |\n" +
+				"|:-|:-:|-:|\n\n" +
+				"#### Requesting addresses\n\n" +
+				"| Address | Count |\n" +
+				"|:-|-:|\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, render.Markdown(tc.info, buf), "report must be rendered")
+
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+)
+
+const metricsNamespace = "nginxparser"
+
+const (
+	metricCounter = "counter"
+	metricGauge   = "gauge"
+	metricSummary = "summary"
+)
+
+var (
+	labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+type metricsWriter struct {
+	out         io.Writer
+	openMetrics bool
+}
+
+type metricLabel struct {
+	name  string
+	value string
+}
+
+func (w *metricsWriter) family(name, metricType, unit, help string) string {
+	name = metricsNamespace + "_" + name
+	if unit != "" {
+		name += "_" + unit
+	}
+
+	sample := name
+	if metricType == metricCounter && !w.openMetrics {
+		name += "_total"
+	}
+
+	fmt.Fprintf(w.out, "# TYPE %s %s\n", name, metricType)
+
+	if unit != "" && w.openMetrics {
+		fmt.Fprintf(w.out, "# UNIT %s %s\n", name, unit)
+	}
+
+	fmt.Fprintf(w.out, "# HELP %s %s\n", name, helpReplacer.Replace(help))
+
+	if metricType == metricCounter {
+		sample += "_total"
+	}
+
+	return sample
+}
+
+func (w *metricsWriter) sample(name string, value int, labels ...metricLabel) {
+	fmt.Fprint(w.out, name)
+
+	if len(labels) != 0 {
+		pairs := make([]string, len(labels))
+		for i, label := range labels {
+			pairs[i] = label.name + `="` + labelReplacer.Replace(label.value) + `"`
+		}
+
+		fmt.Fprintf(w.out, "{%s}", strings.Join(pairs, ","))
+	}
+
+	fmt.Fprintf(w.out, " %d\n", value)
+}
+
+func (w *metricsWriter) write(info *domain.FileInfo) {
+	name := w.family("requests", metricCounter, "", "Number of requests that passed the filters.")
+	w.sample(name, info.TotalRequests)
+
+	name = w.family("requests_by_status", metricCounter, "", "Number of requests by response status code.")
+	for _, status := range info.Statuses {
+		w.sample(name, status.Quantity,
+			metricLabel{name: "code", value: strconv.Itoa(status.Code)},
+			metricLabel{name: "class", value: strconv.Itoa(status.Code/100) + "xx"},
+		)
+	}
+
+	name = w.family("requests_by_method", metricCounter, "", "Number of requests by request method.")
+	for _, method := range info.Methods {
+		w.sample(name, method.Quantity, metricLabel{name: "method", value: method.Name})
+	}
+
+	name = w.family("response_size", metricSummary, "bytes", "Size of response bodies.")
+	for _, quantile := range info.ResponseSizeQuantiles {
+		w.sample(name, quantile.Value,
+			metricLabel{name: "quantile", value: strconv.FormatFloat(quantile.Quantile, 'g', -1, 64)},
+		)
+	}
+
+	w.sample(name+"_sum", info.ResponseSizeSum)
+	w.sample(name+"_count", info.TotalRequests)
+
+	name = w.family("response_size_max", metricGauge, "bytes", "Size of the largest response body.")
+	w.sample(name, info.ResponseSizeMax)
+
+	name = w.family("day_requests", metricGauge, "", "Number of requests per day.")
+	for _, day := range info.RequestsPerDay {
+		w.sample(name, day.Quantity, metricLabel{name: "day", value: day.Day})
+	}
+
+	name = w.family("day_unique_addresses", metricGauge, "", "Estimated number of distinct client addresses per day.")
+	for _, day := range info.RequestsPerDay {
+		w.sample(name, day.UniqueAddresses, metricLabel{name: "day", value: day.Day})
+	}
+
+	name = w.family("unique_addresses", metricGauge, "", "Estimated number of distinct client addresses.")
+	w.sample(name, info.UniqueAddresses)
+
+	name = w.family("unique_visitors", metricGauge, "", "Estimated number of distinct pairs of client address and user agent.")
+	w.sample(name, info.UniqueVisitors)
+
+	name = w.family("unique_urls", metricGauge, "", "Estimated number of distinct requested resources.")
+	w.sample(name, info.UniqueURLs)
+
+	name = w.family("lines", metricCounter, "", "Number of read log lines.")
+	w.sample(name, info.TotalLines)
+
+	name = w.family("malformed_lines", metricCounter, "", "Number of skipped malformed log lines.")
+	for _, malformed := range info.Malformed {
+		w.sample(name, malformed.Count,
+			metricLabel{name: "path", value: malformed.Path},
+			metricLabel{name: "type", value: malformed.Type},
+		)
+	}
+
+	if w.openMetrics {
+		fmt.Fprint(w.out, "# EOF\n")
+	}
+}
+
+func Prometheus(info *domain.FileInfo, out io.Writer) error {
+	w := &metricsWriter{out: out}
+	w.write(info)
+
+	return nil
+}
+
+func OpenMetrics(info *domain.FileInfo, out io.Writer) error {
+	w := &metricsWriter{out: out, openMetrics: true}
+	w.write(info)
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package render_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	info := &domain.FileInfo{
+		TotalRequests:   3,
+		ResponseSize95p: 200,
+		ResponseSizeSum: 300,
+		ResponseSizeQuantiles: []domain.Quantile{
+			domain.NewQuantile(0.5, 50),
+			domain.NewQuantile(0.95, 200),
+		},
+		ResponseSizeMax: 250,
+		Statuses:        []domain.Status{domain.NewStatus(200, 2), domain.NewStatus(499, 1)},
+		Methods:         []domain.Method{domain.NewMethod("GET", 2), domain.NewMethod("POST", 1)},
+		RequestsPerDay:  []domain.DayRequests{{Day: "2024-10-22", Quantity: 3, UniqueAddresses: 2}},
+		TotalLines:      4,
+		UniqueAddresses: 2,
+		UniqueVisitors:  3,
+		UniqueURLs:      1,
+		Malformed:       []domain.Malformed{domain.NewMalformed("C:\\logs\\\"a\".log", "regexp", 1, []int{2})},
+	}
+
+	tt := []struct {
+		name   string
+		render func(*domain.FileInfo, io.Writer) error
+		want   string
+	}{
+		{
+			name:   "prometheus",
+			render: render.Prometheus,
+			want: `# TYPE nginxparser_requests_total counter
+# HELP nginxparser_requests_total Number of requests that passed the filters.
+nginxparser_requests_total 3
+# TYPE nginxparser_requests_by_status_total counter
+# HELP nginxparser_requests_by_status_total Number of requests by response status code.
+nginxparser_requests_by_status_total{code="200",class="2xx"} 2
+nginxparser_requests_by_status_total{code="499",class="4xx"} 1
+# TYPE nginxparser_requests_by_method_total counter
+# HELP nginxparser_requests_by_method_total Number of requests by request method.
+nginxparser_requests_by_method_total{method="GET"} 2
+nginxparser_requests_by_method_total{method="POST"} 1
+# TYPE nginxparser_response_size_bytes summary
+# HELP nginxparser_response_size_bytes Size of response bodies.
+nginxparser_response_size_bytes{quantile="0.5"} 50
+nginxparser_response_size_bytes{quantile="0.95"} 200
+nginxparser_response_size_bytes_sum 300
+nginxparser_response_size_bytes_count 3
+# TYPE nginxparser_response_size_max_bytes gauge
+# HELP nginxparser_response_size_max_bytes Size of the largest response body.
+nginxparser_response_size_max_bytes 250
+# TYPE nginxparser_day_requests gauge
+# HELP nginxparser_day_requests Number of requests per day.
+nginxparser_day_requests{day="2024-10-22"} 3
+# TYPE nginxparser_day_unique_addresses gauge
+# HELP nginxparser_day_unique_addresses Estimated number of distinct client addresses per day.
+nginxparser_day_unique_addresses{day="2024-10-22"} 2
+# TYPE nginxparser_unique_addresses gauge
+# HELP nginxparser_unique_addresses Estimated number of distinct client addresses.
+nginxparser_unique_addresses 2
+# TYPE nginxparser_unique_visitors gauge
+# HELP nginxparser_unique_visitors Estimated number of distinct pairs of client address and user agent.
+nginxparser_unique_visitors 3
+# TYPE nginxparser_unique_urls gauge
+# HELP nginxparser_unique_urls Estimated number of distinct requested resources.
+nginxparser_unique_urls 1
+# TYPE nginxparser_lines_total counter
+# HELP nginxparser_lines_total Number of read log lines.
+nginxparser_lines_total 4
+# TYPE nginxparser_malformed_lines_total counter
+# HELP nginxparser_malformed_lines_total Number of skipped malformed log lines.
+nginxparser_malformed_lines_total{path="C:\\logs\\\"a\".log",type="regexp"} 1
+`,
+		},
+		{
+			name:   "openmetrics",
+			render: render.OpenMetrics,
+			want: `# TYPE nginxparser_requests counter
+# HELP nginxparser_requests Number of requests that passed the filters.
+nginxparser_requests_total 3
+# TYPE nginxparser_requests_by_status counter
+# HELP nginxparser_requests_by_status Number of requests by response status code.
+nginxparser_requests_by_status_total{code="200",class="2xx"} 2
+nginxparser_requests_by_status_total{code="499",class="4xx"} 1
+# TYPE nginxparser_requests_by_method counter
+# HELP nginxparser_requests_by_method Number of requests by request method.
+nginxparser_requests_by_method_total{method="GET"} 2
+nginxparser_requests_by_method_total{method="POST"} 1
+# TYPE nginxparser_response_size_bytes summary
+# UNIT nginxparser_response_size_bytes bytes
+# HELP nginxparser_response_size_bytes Size of response bodies.
+nginxparser_response_size_bytes{quantile="0.5"} 50
+nginxparser_response_size_bytes{quantile="0.95"} 200
+nginxparser_response_size_bytes_sum 300
+nginxparser_response_size_bytes_count 3
+# TYPE nginxparser_response_size_max_bytes gauge
+# UNIT nginxparser_response_size_max_bytes bytes
+# HELP nginxparser_response_size_max_bytes Size of the largest response body.
+nginxparser_response_size_max_bytes 250
+# TYPE nginxparser_day_requests gauge
+# HELP nginxparser_day_requests Number of requests per day.
+nginxparser_day_requests{day="2024-10-22"} 3
+# TYPE nginxparser_day_unique_addresses gauge
+# HELP nginxparser_day_unique_addresses Estimated number of distinct client addresses per day.
+nginxparser_day_unique_addresses{day="2024-10-22"} 2
+# TYPE nginxparser_unique_addresses gauge
+# HELP nginxparser_unique_addresses Estimated number of distinct client addresses.
+nginxparser_unique_addresses 2
+# TYPE nginxparser_unique_visitors gauge
+# HELP nginxparser_unique_visitors Estimated number of distinct pairs of client address and user agent.
+nginxparser_unique_visitors 3
+# TYPE nginxparser_unique_urls gauge
+# HELP nginxparser_unique_urls Estimated number of distinct requested resources.
+nginxparser_unique_urls 1
+# TYPE nginxparser_lines counter
+# HELP nginxparser_lines Number of read log lines.
+nginxparser_lines_total 4
+# TYPE nginxparser_malformed_lines counter
+# HELP nginxparser_malformed_lines Number of skipped malformed log lines.
+nginxparser_malformed_lines_total{path="C:\\logs\\\"a\".log",type="regexp"} 1
+# EOF
+`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, tc.render(info, buf), "metrics must be rendered")
+
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
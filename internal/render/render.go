@@ -0,0 +1,79 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/pkg/report"
+)
+
+func init() {
+	report.MustRegister("md", "Markdown tables", report.ReporterFunc(Markdown), "markdown")
+	report.MustRegister("adoc", "AsciiDoc tables", report.ReporterFunc(Adoc), "asciidoc")
+	report.MustRegister("json", "JSON described by schema/report.schema.json", report.ReporterFunc(JSON))
+	report.MustRegister("html", "self-contained HTML page with charts", report.ReporterFunc(HTML))
+	report.MustRegister("prometheus", "Prometheus text format for the node_exporter textfile collector",
+		report.ReporterFunc(Prometheus))
+	report.MustRegister("openmetrics", "OpenMetrics text format", report.ReporterFunc(OpenMetrics))
+}
+
+func metricHeader(info *domain.FileInfo) string {
+	switch info.TopMetric {
+	case "bytes":
+		return "Bytes"
+
+	case "errors":
+		return "Errors"
+
+	default:
+		return ""
+	}
+}
+
+func metricValue(info *domain.FileInfo, bytes, errors int) int {
+	if info.TopMetric == "errors" {
+		return errors
+	}
+
+	return bytes
+}
+
+func heavyHittersNote(info *domain.FileInfo, kind string, bound int) string {
+	if info.HeavyHitters == nil {
+		return ""
+	}
+
+	unit := info.TopMetric
+	if unit == "" || unit == "count" {
+		unit = "requests"
+	}
+
+	return fmt.Sprintf("Approximate: at most %d %s are tracked, counts may be overestimated by up to the max error and "+
+		"unlisted %s have at most %d %s.", info.HeavyHitters.Capacity, kind, kind, bound, unit)
+}
+
+func statusCode(status *domain.Status) string {
+	if status.Code == 0 {
+		return "-"
+	}
+
+	return strconv.Itoa(status.Code)
+}
+
+func quantileName(quantile float64) string {
+	return "p" + strconv.FormatFloat(math.Round(quantile*1e5)/1e3, 'f', -1, 64)
+}
+
+type bucketSummary struct {
+	name   string
+	bucket *domain.TimeBucket
+}
+
+func timeSeriesSummary(series *domain.TimeSeries) []bucketSummary {
+	return []bucketSummary{
+		{name: "Peak", bucket: &series.Peak},
+		{name: "Quietest", bucket: &series.Quietest},
+	}
+}
@@ -0,0 +1,162 @@
+package render
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/pkg/report"
+)
+
+type executor interface {
+	Execute(out io.Writer, data any) error
+}
+
+type templateReporter struct {
+	tmpl executor
+}
+
+func (r *templateReporter) Report(info *domain.FileInfo, out io.Writer) error {
+	if err := r.tmpl.Execute(out, info); err != nil {
+		return fmt.Errorf("execute template: %w", err)
+	}
+
+	return nil
+}
+
+func isHTMLTemplate(path string) bool {
+	path = strings.TrimSuffix(strings.ToLower(path), ".tmpl")
+	ext := filepath.Ext(path)
+
+	return ext == ".html" || ext == ".htm"
+}
+
+func NewTemplate(path string) (report.Reporter, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read template: %w", err)
+	}
+
+	name := filepath.Base(path)
+
+	if isHTMLTemplate(path) {
+		tmpl, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(templateFuncs)).Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("parse html template: %w", err)
+		}
+
+		return &templateReporter{tmpl: tmpl}, nil
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+
+	return &templateReporter{tmpl: tmpl}, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"bytes":    humanBytes,
+	"percent":  percent,
+	"padLeft":  padLeft,
+	"padRight": padRight,
+	"sortBy":   sortBy,
+	"join":     join,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"repeat":   strings.Repeat,
+	"add":      func(a, b int) int { return a + b },
+	"sub":      func(a, b int) int { return a - b },
+}
+
+func percent(part, total int) string {
+	if total == 0 {
+		return "0.0%"
+	}
+
+	return fmt.Sprintf("%.1f%%", 100*float64(part)/float64(total))
+}
+
+func padLeft(width int, value any) string {
+	str := fmt.Sprint(value)
+	return strings.Repeat(" ", max(width-utf8.RuneCountInString(str), 0)) + str
+}
+
+func padRight(width int, value any) string {
+	str := fmt.Sprint(value)
+	return str + strings.Repeat(" ", max(width-utf8.RuneCountInString(str), 0))
+}
+
+func join(sep string, values any) (string, error) {
+	list := reflect.ValueOf(values)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return "", NewErrTemplate(fmt.Sprintf("join: %T is not a list", values))
+	}
+
+	strs := make([]string, list.Len())
+	for i := range strs {
+		strs[i] = fmt.Sprint(list.Index(i).Interface())
+	}
+
+	return strings.Join(strs, sep), nil
+}
+
+func lessValues(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() < b.Uint()
+
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+
+	default:
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	}
+}
+
+func sortBy(key string, values any) (any, error) {
+	field, desc := strings.CutPrefix(key, "-")
+
+	list := reflect.ValueOf(values)
+	if list.Kind() != reflect.Slice {
+		return nil, NewErrTemplate(fmt.Sprintf("sortBy: %T is not a list", values))
+	}
+
+	elemType := list.Type().Elem()
+	if elemType.Kind() != reflect.Struct {
+		return nil, NewErrTemplate(fmt.Sprintf("sortBy: %s is not a struct", elemType))
+	}
+
+	structField, ok := elemType.FieldByName(field)
+	if !ok {
+		return nil, NewErrTemplate(fmt.Sprintf("sortBy: %s has no field %q", elemType, field))
+	}
+
+	sorted := reflect.MakeSlice(list.Type(), list.Len(), list.Len())
+	reflect.Copy(sorted, list)
+
+	sort.SliceStable(sorted.Interface(), func(i, j int) bool {
+		a := sorted.Index(i).FieldByIndex(structField.Index)
+		b := sorted.Index(j).FieldByIndex(structField.Index)
+
+		if desc {
+			return lessValues(b, a)
+		}
+
+		return lessValues(a, b)
+	})
+
+	return sorted.Interface(), nil
+}
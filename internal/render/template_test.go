@@ -0,0 +1,120 @@
+package render_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+	"github.com/LLIEPJIOK/nginxparser/internal/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate(t *testing.T) {
+	info := &domain.FileInfo{
+		Paths:           []string{"a.log", "b.log"},
+		TotalRequests:   8,
+		AvgResponseSize: 1536,
+		FrequentURLs: []domain.URL{
+			domain.NewURL("/b", 2),
+			domain.NewURL("/<a>", 5),
+			domain.NewURL("/c", 1),
+		},
+	}
+
+	tt := []struct {
+		name     string
+		file     string
+		template string
+		expected string
+	}{
+		{
+			name:     "join and bytes",
+			file:     "report.tmpl",
+			template: `{{join ", " .Paths}}: {{bytes .AvgResponseSize}}`,
+			expected: "a.log, b.log: 1.5 KiB",
+		},
+		{
+			name: "sort, pad and percent",
+			file: "report.txt.tmpl",
+			template: `{{range sortBy "-Quantity" .FrequentURLs}}{{padRight 5 .Name}}|{{padLeft 3 .Quantity}}|` +
+				`{{percent .Quantity $.TotalRequests}}
+{{end}}`,
+			expected: "/<a> |  5|62.5%\n/b   |  2|25.0%\n/c   |  1|12.5%\n",
+		},
+		{
+			name:     "sort ascending by name",
+			file:     "report.tmpl",
+			template: `{{range sortBy "Name" .FrequentURLs}}{{.Name}} {{end}}`,
+			expected: "/<a> /b /c ",
+		},
+		{
+			name:     "html escaping",
+			file:     "report.html.tmpl",
+			template: `<ul>{{range .FrequentURLs}}<li>{{.Name | upper}}</li>{{end}}</ul>`,
+			expected: "<ul><li>/B</li><li>/&lt;A&gt;</li><li>/C</li></ul>",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			require.NoError(t, os.WriteFile(path, []byte(tc.template), 0o600), "template must be written")
+
+			reporter, err := render.NewTemplate(path)
+			require.NoError(t, err, "template must be parsed")
+
+			buf := &bytes.Buffer{}
+			require.NoError(t, reporter.Report(info, buf), "template must be executed")
+
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+
+	assert.Equal(t, "/b", info.FrequentURLs[0].Name)
+}
+
+func TestTemplateError(t *testing.T) {
+	tt := []struct {
+		name     string
+		template string
+		parseErr bool
+	}{
+		{
+			name:     "syntax error",
+			template: `{{range .Paths}}`,
+			parseErr: true,
+		},
+		{
+			name:     "unknown sort field",
+			template: `{{sortBy "Size" .FrequentURLs}}`,
+		},
+		{
+			name:     "sort not a list",
+			template: `{{sortBy "Name" .TotalRequests}}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.tmpl")
+			require.NoError(t, os.WriteFile(path, []byte(tc.template), 0o600), "template must be written")
+
+			reporter, err := render.NewTemplate(path)
+			if tc.parseErr {
+				require.Error(t, err, "template must not be parsed")
+				return
+			}
+
+			require.NoError(t, err, "template must be parsed")
+
+			err = reporter.Report(&domain.FileInfo{FrequentURLs: []domain.URL{domain.NewURL("/", 1)}}, &bytes.Buffer{})
+			require.ErrorAs(t, err, &render.ErrTemplate{})
+		})
+	}
+
+	_, err := render.NewTemplate(filepath.Join(t.TempDir(), "missing.tmpl"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
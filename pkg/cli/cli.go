@@ -0,0 +1,9 @@
+package cli
+
+import (
+	"github.com/LLIEPJIOK/nginxparser/internal/application/parser"
+)
+
+func Run() error {
+	return parser.Start()
+}
@@ -0,0 +1,29 @@
+package report
+
+type ErrRegister struct {
+	msg string
+}
+
+func NewErrRegister(msg string) error {
+	return ErrRegister{
+		msg: msg,
+	}
+}
+
+func (e ErrRegister) Error() string {
+	return e.msg
+}
+
+type ErrUnknownFormat struct {
+	msg string
+}
+
+func NewErrUnknownFormat(msg string) error {
+	return ErrUnknownFormat{
+		msg: msg,
+	}
+}
+
+func (e ErrUnknownFormat) Error() string {
+	return e.msg
+}
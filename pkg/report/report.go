@@ -0,0 +1,131 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/LLIEPJIOK/nginxparser/internal/domain"
+)
+
+type (
+	FileInfo     = domain.FileInfo
+	URL          = domain.URL
+	Status       = domain.Status
+	Address      = domain.Address
+	Method       = domain.Method
+	SourceFormat = domain.SourceFormat
+	Malformed    = domain.Malformed
+	DayRequests  = domain.DayRequests
+	StatusClass  = domain.StatusClass
+	SizeBucket   = domain.SizeBucket
+	Quantile     = domain.Quantile
+	HeavyHitters = domain.HeavyHitters
+	TimeBucket   = domain.TimeBucket
+	TimeSeries   = domain.TimeSeries
+)
+
+type Reporter interface {
+	Report(info *FileInfo, out io.Writer) error
+}
+
+type ReporterFunc func(info *FileInfo, out io.Writer) error
+
+func (f ReporterFunc) Report(info *FileInfo, out io.Writer) error {
+	return f(info, out)
+}
+
+type Format struct {
+	Name        string
+	Aliases     []string
+	Description string
+	Reporter    Reporter
+}
+
+var registry = struct {
+	mu      sync.RWMutex
+	formats map[string]*Format
+	names   map[string]*Format
+}{
+	formats: make(map[string]*Format),
+	names:   make(map[string]*Format),
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func Register(name, description string, reporter Reporter, aliases ...string) error {
+	if reporter == nil {
+		return NewErrRegister(fmt.Sprintf("format %q: nil reporter", name))
+	}
+
+	format := &Format{
+		Name:        normalize(name),
+		Description: description,
+		Reporter:    reporter,
+	}
+
+	names := []string{format.Name}
+
+	for _, alias := range aliases {
+		format.Aliases = append(format.Aliases, normalize(alias))
+		names = append(names, normalize(alias))
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for _, name := range names {
+		if name == "" {
+			return NewErrRegister("empty format name")
+		}
+
+		if _, ok := registry.names[name]; ok {
+			return NewErrRegister(fmt.Sprintf("format %q is already registered", name))
+		}
+	}
+
+	registry.formats[format.Name] = format
+	for _, name := range names {
+		registry.names[name] = format
+	}
+
+	return nil
+}
+
+func MustRegister(name, description string, reporter Reporter, aliases ...string) {
+	if err := Register(name, description, reporter, aliases...); err != nil {
+		panic(err)
+	}
+}
+
+func Lookup(name string) (Reporter, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	format, ok := registry.names[normalize(name)]
+	if !ok {
+		return nil, NewErrUnknownFormat(fmt.Sprintf("unknown format %q", name))
+	}
+
+	return format.Reporter, nil
+}
+
+func Formats() []Format {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	formats := make([]Format, 0, len(registry.formats))
+	for _, format := range registry.formats {
+		formats = append(formats, *format)
+	}
+
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i].Name < formats[j].Name
+	})
+
+	return formats
+}
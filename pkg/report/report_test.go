@@ -0,0 +1,118 @@
+package report_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/LLIEPJIOK/nginxparser/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func totalReporter(info *report.FileInfo, out io.Writer) error {
+	_, err := fmt.Fprintf(out, "total=%d", info.TotalRequests)
+	return err
+}
+
+func peakReporter(info *report.FileInfo, out io.Writer) error {
+	if info.TimeSeries == nil {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(out, "peak=%s:%d", info.TimeSeries.Peak.Start, info.TimeSeries.Peak.Quantity)
+
+	return err
+}
+
+func TestRegister(t *testing.T) {
+	require.NoError(t, report.Register("Total", "number of requests", report.ReporterFunc(totalReporter), "count"))
+
+	tt := []struct {
+		name string
+	}{
+		{name: "total"},
+		{name: "TOTAL"},
+		{name: "count"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter, err := report.Lookup(tc.name)
+			require.NoError(t, err, "registered format must be found")
+
+			buf := &bytes.Buffer{}
+			require.NoError(t, reporter.Report(&report.FileInfo{TotalRequests: 7}, buf))
+			assert.Equal(t, "total=7", buf.String())
+		})
+	}
+
+	var formats []string
+	for _, format := range report.Formats() {
+		formats = append(formats, format.Name)
+
+		if format.Name == "total" {
+			assert.Equal(t, []string{"count"}, format.Aliases)
+			assert.Equal(t, "number of requests", format.Description)
+		}
+	}
+
+	assert.Contains(t, formats, "total")
+	assert.IsNonDecreasing(t, formats)
+}
+
+func TestReporterTypes(t *testing.T) {
+	peak := report.TimeBucket{Start: "2024-10-22 09:00", Quantity: 3}
+	info := &report.FileInfo{
+		ResponseSizeQuantiles: []report.Quantile{{Quantile: 0.5, Value: 100}},
+		HeavyHitters:          &report.HeavyHitters{Capacity: 10},
+		TimeSeries:            &report.TimeSeries{Bucket: "hour", Buckets: []report.TimeBucket{peak}, Peak: peak, Quietest: peak},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.ReporterFunc(peakReporter).Report(info, buf))
+	assert.Equal(t, "peak=2024-10-22 09:00:3", buf.String())
+}
+
+func TestRegisterError(t *testing.T) {
+	require.NoError(t, report.Register("dup", "", report.ReporterFunc(totalReporter)))
+
+	tt := []struct {
+		name     string
+		format   string
+		reporter report.Reporter
+		aliases  []string
+	}{
+		{
+			name:     "duplicate name",
+			format:   "dup",
+			reporter: report.ReporterFunc(totalReporter),
+		},
+		{
+			name:     "duplicate alias",
+			format:   "other",
+			reporter: report.ReporterFunc(totalReporter),
+			aliases:  []string{"DUP"},
+		},
+		{
+			name:     "empty name",
+			format:   " ",
+			reporter: report.ReporterFunc(totalReporter),
+		},
+		{
+			name:   "nil reporter",
+			format: "nil",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := report.Register(tc.format, "", tc.reporter, tc.aliases...)
+			require.ErrorAs(t, err, &report.ErrRegister{})
+		})
+	}
+
+	_, err := report.Lookup("other")
+	require.ErrorAs(t, err, &report.ErrUnknownFormat{}, "failed registration must not be partially applied")
+}